@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/yaml"
+)
+
+// userConfig holds the user preferences from the config file. The values
+// given on the command line take precedence.
+type userConfig struct {
+	Theme   string `json:"theme,omitempty"`
+	Symbols string `json:"symbols,omitempty"`
+}
+
+// userConfigPath returns the path to the config file: $KUBE_HEALTH_CONFIG
+// or the user-specific one (e.g. ~/.config/kube-health/config.yaml).
+func userConfigPath() string {
+	if path := os.Getenv("KUBE_HEALTH_CONFIG"); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "kube-health", "config.yaml")
+}
+
+// loadUserConfig reads the config file. A missing file is not an error.
+func loadUserConfig(path string) (userConfig, error) {
+	var cfg userConfig
+	if path == "" {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// applyUserConfig fills in the flags not given on the command line from
+// the config file.
+func (f *flags) applyUserConfig() error {
+	cfg, err := loadUserConfig(userConfigPath())
+	if err != nil {
+		return err
+	}
+	if f.theme == "" {
+		f.theme = cfg.Theme
+	}
+	if f.symbols == "" {
+		f.symbols = cfg.Symbols
+	}
+	return nil
+}
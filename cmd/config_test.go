@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadUserConfig(t *testing.T) {
+	cfg, err := loadUserConfig("")
+	assert.NoError(t, err)
+	assert.Equal(t, userConfig{}, cfg)
+
+	// A missing file is not an error.
+	cfg, err = loadUserConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.NoError(t, err)
+	assert.Equal(t, userConfig{}, cfg)
+
+	cfg, err = loadUserConfig(writeConfig(t, "theme: light\nsymbols: ascii\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, userConfig{Theme: "light", Symbols: "ascii"}, cfg)
+
+	// Unknown keys are reported, e.g. typos.
+	path := writeConfig(t, "theme: light\nsymbol: ascii\n")
+	_, err = loadUserConfig(path)
+	assert.ErrorContains(t, err, "invalid config file "+path)
+	assert.ErrorContains(t, err, `unknown field "symbol"`)
+
+	_, err = loadUserConfig(writeConfig(t, "theme: [light"))
+	assert.ErrorContains(t, err, "invalid config file")
+}
+
+func TestApplyUserConfig(t *testing.T) {
+	t.Setenv("KUBE_HEALTH_CONFIG", writeConfig(t, "theme: light\nsymbols: ascii\n"))
+
+	f := newFlags()
+	f.addFlags(&cobra.Command{})
+	require.NoError(t, f.applyUserConfig())
+	assert.Equal(t, "light", f.theme)
+	assert.Equal(t, "ascii", f.symbols)
+
+	// The command line takes precedence.
+	f = newFlags()
+	f.addFlags(&cobra.Command{})
+	f.theme = "dark"
+	require.NoError(t, f.applyUserConfig())
+	assert.Equal(t, "dark", f.theme)
+	assert.Equal(t, "ascii", f.symbols)
+
+	// An invalid theme in the config file fails the validation.
+	t.Setenv("KUBE_HEALTH_CONFIG", writeConfig(t, "theme: solarized\n"))
+	f = newFlags()
+	f.addFlags(&cobra.Command{})
+	assert.ErrorContains(t, f.validatePrintFlags(), `unknown theme "solarized"`)
+}
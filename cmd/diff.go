@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/kubectl/pkg/cmd/util"
+
+	"github.com/inecas/kube-health/pkg/analyze"
+	"github.com/inecas/kube-health/pkg/api/v1alpha1"
+	"github.com/inecas/kube-health/pkg/diff"
+	"github.com/inecas/kube-health/pkg/print"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+type diffFlags struct {
+	fromContext string
+	toContext   string
+}
+
+func newDiffCommand(fl *flags) *cobra.Command {
+	df := &diffFlags{}
+	cmd := &cobra.Command{
+		Use:   "diff (OLD_REPORT [NEW_REPORT] | --from-context=CONTEXT --to-context=CONTEXT RESOURCE...)",
+		Short: "Compare the health between two points in time or two clusters",
+		Long: `Compare the health between two points in time or two clusters.
+
+The reports are the structured output of kube-health (-o json or -o yaml).
+With a single report, it's compared with the current state of its objects.
+With --from-context and --to-context, the resources are evaluated in both
+clusters, e.g. to compare staging and production.
+
+The objects are matched by their kind, namespace and name. The exit code
+is 1 if there are differences.`,
+		SilenceUsage: true,
+		RunE:         runDiffFunc(fl, df),
+	}
+
+	cmd.Flags().StringVar(&df.fromContext, "from-context", "",
+		"Kubeconfig context to evaluate the old state in")
+	cmd.Flags().StringVar(&df.toContext, "to-context", "",
+		"Kubeconfig context to evaluate the new state in")
+	cmd.Flags().StringVarP(fl.printFlags.OutputFormat, "output", "o", *fl.printFlags.OutputFormat,
+		"Output format. One of: (tree, tree+color).")
+	return cmd
+}
+
+func runDiffFunc(fl *flags, df *diffFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, posArgs []string) error {
+		fl.printFlags.OutputFlagSpecified = func() bool {
+			return cmd.Flags().Changed("output")
+		}
+		switch *fl.printFlags.OutputFormat {
+		case "tree", "tree+color":
+		default:
+			return fmt.Errorf("unsupported output format %q for diff", *fl.printFlags.OutputFormat)
+		}
+		if err := fl.validatePrintFlags(); err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		if err := fl.registerAnalyzers(ctx, util.NewFactory(fl.configFlags)); err != nil {
+			return err
+		}
+
+		var oldName, newName string
+		var oldStatuses, newStatuses []status.ObjectStatus
+		var err error
+
+		if df.fromContext != "" || df.toContext != "" {
+			if df.fromContext == "" || df.toContext == "" {
+				return fmt.Errorf("both --from-context and --to-context need to be specified")
+			}
+			posArgs, err = fl.applyOverviewTarget(posArgs)
+			if err != nil {
+				return err
+			}
+			if err := fl.validateResourceArgs(posArgs); err != nil {
+				return err
+			}
+			oldName, newName = "context "+df.fromContext, "context "+df.toContext
+
+			oldStatuses, err = fl.evaluateOnce(ctx, fl.configFlagsForContext(df.fromContext), posArgs)
+			if err != nil {
+				return err
+			}
+			newStatuses, err = fl.evaluateOnce(ctx, fl.configFlagsForContext(df.toContext), posArgs)
+			if err != nil {
+				return err
+			}
+		} else {
+			if len(posArgs) < 1 || len(posArgs) > 2 {
+				return fmt.Errorf("expected one or two reports")
+			}
+			oldName = posArgs[0]
+			oldReport, err := readReport(posArgs[0])
+			if err != nil {
+				return err
+			}
+			oldStatuses = oldReport.ToObjectStatuses()
+
+			if len(posArgs) == 2 {
+				newName = posArgs[1]
+				newReport, err := readReport(posArgs[1])
+				if err != nil {
+					return err
+				}
+				newStatuses = newReport.ToObjectStatuses()
+			} else {
+				newName = "current state"
+				newStatuses, err = fl.reevaluate(ctx, oldStatuses)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		diffs := diff.Diff(oldStatuses, newStatuses)
+
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "--- %s\n+++ %s\n\n", oldName, newName)
+		print.NewDiffPrinter(fl.printOpts()).PrintDiff(diffs, out)
+
+		exitCode = 0
+		for _, d := range diffs {
+			if d.HasChanges() {
+				exitCode = 1
+			}
+		}
+		return nil
+	}
+}
+
+func readReport(path string) (*v1alpha1.HealthReport, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	report, err := v1alpha1.ReadHealthReport(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return report, nil
+}
+
+// evaluateOnce evaluates the objects selected by the arguments and flags
+// in the cluster given by the config flags, without waiting.
+func (f *flags) evaluateOnce(ctx context.Context, cf *genericclioptions.ConfigFlags,
+	posArgs []string) ([]status.ObjectStatus, error) {
+	factory := util.NewFactory(cf)
+	evaluator, err := f.newEvaluator(factory)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace, explicitNamespace, err := factory.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return nil, err
+	}
+	if f.overview {
+		return evaluator.EvalQuery(ctx, analyze.OverviewQuerySpec(f.overviewNamespace(namespace)), nil)
+	}
+	filenameOpts := &resource.FilenameOptions{
+		Filenames: f.filenames,
+		Recursive: f.recursive,
+	}
+
+	var ret []status.ObjectStatus
+	for _, obj := range f.loadObjects(cf, namespace, explicitNamespace, posArgs, filenameOpts) {
+		ret = append(ret, evaluator.Eval(ctx, obj))
+	}
+	return ret, nil
+}
+
+// reevaluate evaluates the current state of the top-level objects from
+// a saved report. Objects that no longer exist are left out.
+func (f *flags) reevaluate(ctx context.Context, statuses []status.ObjectStatus) ([]status.ObjectStatus, error) {
+	evaluator, err := f.newEvaluator(util.NewFactory(f.configFlags))
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []status.ObjectStatus
+	for _, s := range statuses {
+		os := evaluator.Eval(ctx, s.Object)
+		if apierrors.IsNotFound(os.ObjStatus.Err) {
+			continue
+		}
+		ret = append(ret, os)
+	}
+	return ret, nil
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/resource"
+
+	"github.com/inecas/kube-health/pkg/analyze"
+	"github.com/inecas/kube-health/pkg/analyze/predict"
+	"github.com/inecas/kube-health/pkg/eval"
+	"github.com/inecas/kube-health/pkg/print"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+const fieldManager = "kube-health"
+
+// validateDryRun rejects the flags that have no effect with --dry-run: the
+// objects are evaluated only once, so there is nothing to wait for.
+func (f *flags) validateDryRun() error {
+	switch {
+	case f.waitProgress:
+		return fmt.Errorf("--wait-progress is not supported with --dry-run")
+	case f.waitOk:
+		return fmt.Errorf("--wait-ok is not supported with --dry-run")
+	case f.waitForever:
+		return fmt.Errorf("--wait-forever is not supported with --dry-run")
+	case len(f.waitFor) > 0:
+		return fmt.Errorf("--wait-for is not supported with --dry-run")
+	case f.waitStable > 0:
+		return fmt.Errorf("--wait-stable is not supported with --dry-run")
+	case f.timeout > 0:
+		return fmt.Errorf("--timeout is not supported with --dry-run")
+	}
+	return nil
+}
+
+// runDryRun applies the manifests using server-side dry-run and predicts
+// the health of the resulting objects.
+func (f *flags) runDryRun(ctx context.Context, evaluator *eval.Evaluator, namespace string, explicitNamespace bool,
+	filenameOpts *resource.FilenameOptions, printer print.StatusPrinter, w io.Writer, failOn status.Result) error {
+	var objects []*status.Object
+	var rejected []status.ObjectStatus
+
+	err := resource.NewBuilder(f.configFlags).
+		Unstructured().
+		NamespaceParam(namespace).DefaultNamespace().
+		FilenameParam(explicitNamespace, filenameOpts).
+		Flatten().
+		Do().
+		Visit(func(info *resource.Info, err error) error {
+			if err != nil {
+				return err
+			}
+
+			obj, err := dryRunApply(info)
+			if err == nil {
+				objects = append(objects, obj)
+				return nil
+			}
+
+			unst, ok := info.Object.(*unstructured.Unstructured)
+			if !ok {
+				return fmt.Errorf("expected *unstructured.Unstructured, got %T", info.Object)
+			}
+			obj, convErr := status.NewObjectFromUnstructured(unst)
+			if convErr != nil {
+				return convErr
+			}
+			rejected = append(rejected, analyze.AggregateResult(obj, nil, []status.ConditionStatus{
+				analyze.ConditionStatusError(analyze.SyntheticCondition(
+					"DryRunApplied", false, "Rejected", err.Error(), time.Time{})),
+			}))
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	predictor := predict.NewPredictor(evaluator, objects)
+	statuses := rejected
+	for _, obj := range objects {
+		statuses = append(statuses, predictor.Predict(ctx, obj))
+	}
+
+	print.PrintStatusUpdate(printer, eval.StatusUpdate{Statuses: statuses, Duration: time.Since(start)}, w)
+	setExitCode(statuses, failOn)
+	return nil
+}
+
+// dryRunApply runs server-side apply of the object in the dry-run mode and
+// returns the object as it would be persisted.
+func dryRunApply(info *resource.Info) (*status.Object, error) {
+	data, err := runtime.Encode(unstructured.UnstructuredJSONScheme, info.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	force := true
+	ret, err := resource.NewHelper(info.Client, info.Mapping).
+		DryRun(true).
+		WithFieldManager(fieldManager).
+		Patch(info.Namespace, info.Name, types.ApplyPatchType, data, &metav1.PatchOptions{Force: &force})
+	if err != nil {
+		return nil, err
+	}
+
+	unst, ok := ret.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("expected *unstructured.Unstructured, got %T", ret)
+	}
+	return status.NewObjectFromUnstructured(unst)
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDryRun(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{nil, ""},
+		// The exit code is based on the predicted results.
+		{[]string{"--fail-on=error"}, ""},
+		{[]string{"--wait-progress"}, "--wait-progress is not supported with --dry-run"},
+		{[]string{"--wait-ok"}, "--wait-ok is not supported with --dry-run"},
+		{[]string{"--wait-forever"}, "--wait-forever is not supported with --dry-run"},
+		{[]string{"--wait-for=condition=Available"}, "--wait-for is not supported with --dry-run"},
+		{[]string{"--wait-stable=30s"}, "--wait-stable is not supported with --dry-run"},
+		{[]string{"--timeout=5m"}, "--timeout is not supported with --dry-run"},
+	}
+	for _, tt := range tests {
+		f := newFlags()
+		cmd := &cobra.Command{}
+		f.addFlags(cmd)
+		require.NoError(t, cmd.ParseFlags(append([]string{"--dry-run"}, tt.args...)))
+
+		err := f.validateDryRun()
+		if tt.expected == "" {
+			assert.NoError(t, err, tt.args)
+		} else {
+			assert.EqualError(t, err, tt.expected)
+		}
+	}
+}
@@ -2,16 +2,21 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/resource"
 	"k8s.io/klog/v2"
@@ -19,6 +24,9 @@ import (
 	"k8s.io/kubectl/pkg/util/term"
 
 	"github.com/inecas/kube-health/pkg/analyze"
+	"github.com/inecas/kube-health/pkg/analyze/declarative"
+	"github.com/inecas/kube-health/pkg/analyze/plugin"
+	"github.com/inecas/kube-health/pkg/api/v1alpha1"
 	// Extra analyzers for Red Hat related projects.
 	_ "github.com/inecas/kube-health/pkg/analyze/redhat"
 	"github.com/inecas/kube-health/pkg/eval"
@@ -42,12 +50,14 @@ func Execute() {
 		Use:          execName(),
 		Short:        "Monitor Kubernetes resource health",
 		SilenceUsage: true,
+		Args:         cobra.ArbitraryArgs,
 		RunE:         runFunc(flags),
 	}
 
 	flags.addFlags(cmd)
+	cmd.AddCommand(newDiffCommand(flags))
 	if err := cmd.Execute(); err != nil {
-		os.Exit(128)
+		os.Exit(exitCodeFailure)
 	}
 	os.Exit(exitCode)
 }
@@ -60,15 +70,46 @@ func execName() string {
 }
 
 type flags struct {
-	waitForever  bool
-	waitProgress bool
-	waitOk       bool
-	showGroup    bool
-	showOk       bool
-	printVersion bool
-	width        int
-	configFlags  *genericclioptions.ConfigFlags
-	printFlags   *genericclioptions.PrintFlags
+	waitForever   bool
+	waitProgress  bool
+	waitOk        bool
+	waitFor       []string
+	waitStable    time.Duration
+	timeout       time.Duration
+	failOn        string
+	showGroup     bool
+	showOk        bool
+	explain       bool
+	interactive   bool
+	dryRun        bool
+	contexts      []string
+	allContexts   bool
+	printVersion  bool
+	width         int
+	sortBy        string
+	minResult     string
+	maxDepth      int
+	collapse      bool
+	theme         string
+	symbols       string
+	fullMessages  bool
+	logLines      int64
+	previousLogs  bool
+	aggregation   []string
+	analyzers     []string
+	pluginDirs    []string
+	noPlugins     bool
+	pathPlugins   bool
+	selector      string
+	fieldSelector string
+	allNamespaces bool
+	overview      bool
+	filenames     []string
+	recursive     bool
+	includeKinds  []string
+	excludeKinds  []string
+	configFlags   *genericclioptions.ConfigFlags
+	printFlags    *genericclioptions.PrintFlags
 }
 
 func newFlags() *flags {
@@ -90,14 +131,85 @@ func (f *flags) addFlags(cmd *cobra.Command) {
 		"Wait until the resources are ready (success only)")
 	fs.BoolVarP(&f.waitForever, "wait-forever", "F", false,
 		"Wait forever")
+	fs.StringArrayVar(&f.waitFor, "wait-for", nil,
+		"Wait until all the objects have the condition in the status, in condition=TYPE[=STATUS] format "+
+			"(e.g. condition=Available). Can be repeated")
+	fs.DurationVar(&f.waitStable, "wait-stable", 0,
+		"Require the awaited state to hold for the duration before exiting (e.g. 30s). "+
+			"Implies --wait-ok if no other wait condition is specified")
+	fs.DurationVar(&f.timeout, "timeout", 0,
+		"Stop waiting after the duration (e.g. 5m). The exit code has 16 added on timeout. Zero means no timeout")
+	fs.StringVar(&f.failOn, "fail-on", "warning",
+		"Lowest result causing non-zero exit code, including the predicted one with --dry-run. One of: warning, error")
 	fs.BoolVarP(&f.showGroup, "show-group", "G", false,
 		"For each object, show API group it belongs to")
 	fs.BoolVarP(&f.showOk, "show-healthy", "H", false,
 		"Show details for all objects, including those with OK status")
+	fs.StringVar(&f.minResult, "min-result", "",
+		"Hide the objects (with their sub-trees) with lower result in the tree output, keeping the unknown ones. One of: warning, error")
+	fs.IntVar(&f.maxDepth, "max-depth", 0,
+		"Maximum depth of the sub-objects in the tree output. Zero means no limit")
+	fs.BoolVar(&f.collapse, "collapse-identical", false,
+		"Fold the sibling objects with the same status and conditions into a single line in the tree output")
+	fs.StringVar(&f.theme, "theme", "",
+		fmt.Sprintf("Color theme. One of: %s (default dark)", strings.Join(print.ThemeNames(), ", ")))
+	fs.StringVar(&f.symbols, "symbols", "",
+		"Show symbols next to the results, so that they can be told apart without colors. One of: none, unicode, ascii")
+	fs.BoolVar(&f.fullMessages, "full-messages", false,
+		"Show the whole condition messages instead of truncating the long lines")
+	fs.Int64Var(&f.logLines, "log-lines", eval.DefaultPodLogLines,
+		"Number of log lines shown for the unhealthy containers")
+	fs.BoolVar(&f.previousLogs, "previous-logs", false,
+		"Show the logs of the previous instance for the restarted (e.g. crashed) containers")
+	fs.BoolVar(&f.explain, "explain", false,
+		"Show how each result was determined: the analyzer, the matched rule and why it escalated to the parent")
+	fs.BoolVarP(&f.interactive, "interactive", "i", false,
+		"Explore the health in an interactive terminal UI, with live updates")
 	fs.IntVar(&f.width, "width", -1,
 		"Width of the output. By default, it's inferred from the terminal width. Set to 0 to disable wrapping")
+	fs.StringVar(&f.sortBy, "sort-by", "",
+		fmt.Sprintf("Sort the rows of the wide-table and custom-columns output by the field. One of: %s",
+			strings.Join(print.TableFieldNames(), ", ")))
+	fs.StringArrayVar(&f.aggregation, "aggregation", nil,
+		"Policy for aggregating sub-objects of a kind, in RESOURCE=POLICY format (e.g. replicasets.apps=threshold=20). "+
+			"Policies: worst-of, threshold=PERCENT, quorum[=N], any-ready. Can be repeated")
+	fs.StringArrayVar(&f.analyzers, "analyzers-config", nil,
+		"Path to a file with declarative analyzers definitions. Can be repeated")
+	fs.StringArrayVar(&f.pluginDirs, "plugin-dir", nil,
+		"Directory with analyzer plugins (kube-health-analyzer-* executables), searched before "+
+			"the default plugin directory. Can be repeated")
+	fs.BoolVar(&f.pathPlugins, "plugins", false, "Discover analyzer plugins on PATH too")
+	fs.BoolVar(&f.noPlugins, "no-plugins", false, "Disable discovery of analyzer plugins")
 	fs.BoolVar(&f.printVersion, "version", false, "Print version information")
 	fl.AddFlagSet(fs)
+
+	fs = pflag.NewFlagSet("selection", pflag.ExitOnError)
+	fs.StringVarP(&f.selector, "selector", "l", "",
+		"Selector (label query) to filter on, supports '=', '==', '!=', 'in', 'notin'")
+	fs.StringVar(&f.fieldSelector, "field-selector", "",
+		"Selector (field query) to filter on, supports '=', '==', and '!='")
+	fs.BoolVarP(&f.allNamespaces, "all-namespaces", "A", false,
+		"List the requested objects across all namespaces")
+	fs.BoolVar(&f.overview, "overview", false,
+		"Evaluate all the top-level objects (objects without owner references) in the namespace, "+
+			"or in the whole cluster with -A, instead of the given resources. "+
+			"The namespace/<ns> and cluster arguments are shortcuts for it")
+	fs.StringSliceVarP(&f.filenames, "filename", "f", nil,
+		"Filename, directory, or URL to files with the objects to evaluate. Can be repeated")
+	fs.BoolVar(&f.dryRun, "dry-run", false,
+		"Apply the manifests (-f or -) with server-side dry-run and report the predicted problems, "+
+			"e.g. missing referenced objects, instead of evaluating the live objects")
+	fs.BoolVarP(&f.recursive, "recursive", "R", false,
+		"Process the directory used in -f, --filename recursively")
+	fs.StringSliceVar(&f.contexts, "contexts", nil,
+		"Evaluate the resources in multiple clusters given by the kubeconfig contexts, in parallel")
+	fs.BoolVar(&f.allContexts, "all-contexts", false,
+		"Evaluate the resources in all the kubeconfig contexts, in parallel")
+	fs.StringSliceVar(&f.includeKinds, "include-kinds", nil,
+		"Only show sub-objects of these kinds (e.g. pods,replicasets.apps)")
+	fs.StringSliceVar(&f.excludeKinds, "exclude-kinds", nil,
+		"Don't show sub-objects of these kinds (e.g. events,configmaps)")
+	fl.AddFlagSet(fs)
 }
 
 // AddFlags receives a *cobra.Command reference and binds
@@ -106,7 +218,7 @@ func (f *flags) addPrintFlags(cmd *cobra.Command) {
 	f.printFlags.JSONYamlPrintFlags.AddFlags(cmd)
 	f.printFlags.TemplatePrinterFlags.AddFlags(cmd)
 
-	allowedFormats := append([]string{"tree", "tree+color"}, f.printFlags.AllowedFormats()...)
+	allowedFormats := append([]string{"tree", "tree+color", "junit", "tap", "github", "gitlab", "markdown", "html", "dot", "mermaid", "jsonl", "wide-table", "custom-columns=..."}, f.printFlags.AllowedFormats()...)
 
 	if f.printFlags.OutputFormat != nil {
 		cmd.Flags().StringVarP(f.printFlags.OutputFormat, "output", "o", *f.printFlags.OutputFormat,
@@ -128,29 +240,284 @@ func (f *flags) printOpts() print.PrintOptions {
 		}
 	}
 	po := print.PrintOptions{
-		ShowGroup: f.showGroup,
-		ShowOk:    f.showOk,
-		Width:     termWidth,
+		ShowGroup:         f.showGroup,
+		ShowOk:            f.showOk,
+		Width:             termWidth,
+		Explain:           f.explain,
+		MaxDepth:          f.maxDepth,
+		CollapseIdentical: f.collapse,
+		FullMessages:      f.fullMessages,
+	}
+	// The values are validated in validatePrintFlags.
+	po.MinResult, _ = parseMinResult(f.minResult)
+	po.Symbols, _ = print.ParseSymbols(f.symbols)
+	if f.theme != "" {
+		po.Theme, _ = print.ParseTheme(f.theme)
 	}
 
-	if strings.Contains(*f.printFlags.OutputFormat, "+color") {
+	// Unless asked explicitly, don't fill non-terminal outputs with the color
+	// escape sequences. The NO_COLOR convention (https://no-color.org) is
+	// respected as well.
+	if strings.Contains(*f.printFlags.OutputFormat, "+color") &&
+		(f.printFlags.OutputFlagSpecified() || (stdoutIsTerminal() && os.Getenv("NO_COLOR") == "")) {
 		po.Color = true
 	}
 
 	return po
 }
 
+func (f *flags) validatePrintFlags() error {
+	if err := f.applyUserConfig(); err != nil {
+		return err
+	}
+	if _, err := parseMinResult(f.minResult); err != nil {
+		return err
+	}
+	if f.theme != "" {
+		if _, err := print.ParseTheme(f.theme); err != nil {
+			return err
+		}
+	}
+	if _, err := print.ParseSymbols(f.symbols); err != nil {
+		return err
+	}
+	if f.maxDepth < 0 {
+		return fmt.Errorf("invalid max-depth %d: expected a non-negative number", f.maxDepth)
+	}
+	if f.logLines <= 0 {
+		return fmt.Errorf("invalid log-lines %d: expected a positive number", f.logLines)
+	}
+	return nil
+}
+
+// parseMinResult converts the --min-result value. Empty value means
+// no filtering (Unknown).
+func parseMinResult(s string) (status.Result, error) {
+	switch strings.ToLower(s) {
+	case "":
+		return status.Unknown, nil
+	case "warning":
+		return status.Warning, nil
+	case "error":
+		return status.Error, nil
+	default:
+		return status.Unknown, fmt.Errorf("invalid min-result %q: expected warning or error", s)
+	}
+}
+
 func (f *flags) toPrinter() (print.StatusPrinter, error) {
-	switch *f.printFlags.OutputFormat {
+	format := *f.printFlags.OutputFormat
+	if f.sortBy != "" && format != "wide-table" && !strings.HasPrefix(format, "custom-columns=") {
+		return nil, fmt.Errorf("--sort-by is supported only with the wide-table and custom-columns output formats")
+	}
+	if spec, found := strings.CutPrefix(format, "custom-columns="); found {
+		return print.NewCustomColumnsPrinter(f.printOpts(), spec, f.sortBy)
+	}
+
+	switch format {
 	case "tree", "tree+color":
 		return print.NewTreePrinter(f.printOpts()), nil
+	case "junit":
+		return print.NewJUnitPrinter(), nil
+	case "tap":
+		return print.NewTAPPrinter(), nil
+	case "github":
+		return print.NewGitHubPrinter(f.printOpts(), os.Getenv("GITHUB_STEP_SUMMARY")), nil
+	case "gitlab":
+		return print.NewGitLabPrinter(), nil
+	case "markdown":
+		return print.NewMarkdownPrinter(f.printOpts()), nil
+	case "html":
+		return print.NewHTMLPrinter(f.printOpts()), nil
+	case "wide-table":
+		return print.NewWideTablePrinter(f.printOpts(), f.sortBy)
+	case "dot":
+		return print.NewDotPrinter(), nil
+	case "mermaid":
+		return print.NewMermaidPrinter(), nil
+	case "jsonl":
+		return print.NewJSONLinesPrinter(f.reportClusterInfo()), nil
 	default:
 		kubectlPrinter, err := f.printFlags.ToPrinter()
 		if err != nil {
 			return nil, err
 		}
-		return print.KubectlPrinter{Printer: kubectlPrinter}, nil
+		return print.NewKubectlPrinter(kubectlPrinter, f.reportClusterInfo()), nil
+	}
+}
+
+// reportClusterInfo returns the cluster identity for the structured output.
+func (f *flags) reportClusterInfo() *v1alpha1.ClusterInfo {
+	if f.multiCluster() {
+		// The objects are grouped per cluster in the report instead.
+		return nil
+	}
+	return f.clusterInfo()
+}
+
+// stdoutIsTerminal is a variable to be replaced in tests.
+var stdoutIsTerminal = func() bool {
+	return term.TTY{Out: os.Stdout}.IsTerminalOut()
+}
+
+// clusterInfo identifies the cluster based on the kubeconfig. It returns nil
+// if the information is not available.
+func (f *flags) clusterInfo() *v1alpha1.ClusterInfo {
+	rawConfig, err := f.configFlags.ToRawKubeConfigLoader().RawConfig()
+	if err != nil {
+		klog.V(2).ErrorS(err, "Failed to load kubeconfig")
+		return nil
+	}
+
+	info := &v1alpha1.ClusterInfo{Context: rawConfig.CurrentContext}
+	if f.configFlags.Context != nil && *f.configFlags.Context != "" {
+		info.Context = *f.configFlags.Context
+	}
+
+	restConfig, err := f.configFlags.ToRESTConfig()
+	if err != nil {
+		klog.V(2).ErrorS(err, "Failed to load REST config")
+	} else {
+		info.Server = restConfig.Host
+	}
+	return info
+}
+
+// runInfo describes the run for the header and the footer of the output.
+func (f *flags) runInfo(w *waiter) *print.RunInfo {
+	info := &print.RunInfo{Wait: w.strategy(), Started: time.Now()}
+	if !f.multiCluster() {
+		if ci := f.clusterInfo(); ci != nil {
+			info.Context = ci.Context
+		}
+	}
+	return info
+}
+
+// withRunInfo attaches the run info to the printers showing it.
+func withRunInfo(printer print.StatusPrinter, info *print.RunInfo) print.StatusPrinter {
+	switch p := printer.(type) {
+	case *print.TreePrinter:
+		p.Info = info
+	case *print.OverviewPrinter:
+		p.Info = info
+	case *print.KubectlPrinter:
+		p.Info = info
+	case *print.JSONLinesPrinter:
+		p.Info = info
+	}
+	return printer
+}
+
+// withErrOut makes the printers reporting their own failures use the given
+// stream.
+func withErrOut(printer print.StatusPrinter, errOut io.Writer) print.StatusPrinter {
+	if p, ok := printer.(*print.KubectlPrinter); ok {
+		p.ErrOut = errOut
+	}
+	return printer
+}
+
+// checkPrinted sets the failure exit code if the printer couldn't print
+// the report, e.g. due to an invalid template.
+func checkPrinted(printer print.StatusPrinter) {
+	if p, ok := printer.(*print.KubectlPrinter); ok && p.Err() != nil {
+		exitCode = exitCodeFailure
+	}
+}
+
+// registerAnalyzers configures the analyzers register based on the flags.
+func (f *flags) registerAnalyzers(ctx context.Context, factory util.Factory) error {
+	if err := registerDeclarativeAnalyzers(f.analyzers); err != nil {
+		return err
+	}
+
+	if !f.noPlugins {
+		plugin.Register(ctx, analyze.Register, plugin.Dirs(f.pluginDirs, f.pathPlugins)...)
+	}
+
+	if len(f.aggregation) > 0 || len(f.includeKinds) > 0 || len(f.excludeKinds) > 0 {
+		mapper, err := factory.ToRESTMapper()
+		if err != nil {
+			return err
+		}
+		if err := f.registerAggregationPolicies(mapper); err != nil {
+			return err
+		}
+		if err := f.registerKindFilters(mapper); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// registerDeclarativeAnalyzers loads the analyzers from --analyzers-config files.
+func registerDeclarativeAnalyzers(paths []string) error {
+	for _, path := range paths {
+		cfg, err := declarative.LoadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := cfg.Register(analyze.Register); err != nil {
+			return fmt.Errorf("invalid analyzers config %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// registerKindFilters applies the --include-kinds and --exclude-kinds flags
+// to all the sub-objects.
+func (f *flags) registerKindFilters(mapper meta.RESTMapper) error {
+	parseKinds := func(kinds []string) ([]schema.GroupKind, error) {
+		var ret []schema.GroupKind
+		for _, k := range kinds {
+			gk, err := eval.ParseGroupKind(mapper, k)
+			if err != nil {
+				return nil, fmt.Errorf("invalid kind %q: %w", k, err)
+			}
+			ret = append(ret, gk)
+		}
+		return ret, nil
+	}
+
+	included, err := parseKinds(f.includeKinds)
+	if err != nil {
+		return err
+	}
+	excluded, err := parseKinds(f.excludeKinds)
+	if err != nil {
+		return err
+	}
+
+	for i, gk := range included {
+		if slices.Contains(excluded, gk) {
+			return fmt.Errorf("kind %q is both included and excluded", f.includeKinds[i])
+		}
+	}
+
+	analyze.Register.SetIncludedKinds(included...)
+	analyze.Register.SetExcludedKinds(excluded...)
+	return nil
+}
+
+// registerAggregationPolicies applies the policies from --aggregation flags.
+func (f *flags) registerAggregationPolicies(mapper meta.RESTMapper) error {
+	for _, a := range f.aggregation {
+		kindStr, policyStr, found := strings.Cut(a, "=")
+		if !found {
+			return fmt.Errorf("invalid aggregation %q: expected RESOURCE=POLICY", a)
+		}
+		gk, err := eval.ParseGroupKind(mapper, kindStr)
+		if err != nil {
+			return fmt.Errorf("invalid aggregation %q: %w", a, err)
+		}
+		policy, err := analyze.ParseAggregationPolicy(policyStr)
+		if err != nil {
+			return fmt.Errorf("invalid aggregation %q: %w", a, err)
+		}
+		analyze.Register.RegisterAggregationPolicy(gk, policy)
 	}
+	return nil
 }
 
 func runFunc(fl *flags) func(cmd *cobra.Command, args []string) error {
@@ -159,16 +526,37 @@ func runFunc(fl *flags) func(cmd *cobra.Command, args []string) error {
 			PrintVersion()
 			return nil
 		}
-		if len(posArgs) == 0 {
-			return fmt.Errorf("no resources specified")
+		posArgs, err := fl.applyOverviewTarget(posArgs)
+		if err != nil {
+			return err
+		}
+		if err := fl.validateResourceArgs(posArgs); err != nil {
+			return err
 		}
 
-		filenameOpts := &resource.FilenameOptions{}
+		filenameOpts := &resource.FilenameOptions{
+			Filenames: fl.filenames,
+			Recursive: fl.recursive,
+		}
 		if len(posArgs) == 1 && posArgs[0] == "-" {
-			filenameOpts.Filenames = []string{"-"}
+			filenameOpts.Filenames = append(filenameOpts.Filenames, "-")
 			posArgs = nil
 		}
 
+		if err := fl.validatePrintFlags(); err != nil {
+			return err
+		}
+		if fl.interactive {
+			if err := fl.validateInteractive(filenameOpts); err != nil {
+				return err
+			}
+		}
+		if fl.dryRun {
+			if err := fl.validateDryRun(); err != nil {
+				return err
+			}
+		}
+
 		f := util.NewFactory(fl.configFlags)
 
 		namespace, explicitNamespace, err := f.ToRawKubeConfigLoader().Namespace()
@@ -176,142 +564,233 @@ func runFunc(fl *flags) func(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		resources := make([]*resource.Info, 0)
-		objects := make([]*status.Object, 0)
-
-		resource.NewBuilder(fl.configFlags).
-			Unstructured().
-			NamespaceParam(namespace).DefaultNamespace().
-			ResourceTypeOrNameArgs(true, posArgs...).
-			FilenameParam(explicitNamespace, filenameOpts).
-			Flatten().
-			ContinueOnError().
-			Do().
-			Visit(func(info *resource.Info, err error) error {
-				if err != nil {
-					return err
-				}
-				resources = append(resources, info)
-
-				unst, ok := info.Object.(*unstructured.Unstructured)
-				if !ok {
-					return fmt.Errorf("expected *unstructured.Unstructured, got %T", info.Object)
-				}
-
-				obj, err := status.NewObjectFromUnstructured(unst)
-				if err != nil {
-					return err
-				}
-				objects = append(objects, obj)
-				return nil
-			})
+		if err := fl.registerAnalyzers(cmd.Context(), f); err != nil {
+			return err
+		}
 
 		ctx := cmd.Context()
 		ctx, cancelFunc := context.WithCancel(ctx)
 		defer cancelFunc()
+		if fl.timeout > 0 {
+			ctx, cancelFunc = context.WithTimeout(ctx, fl.timeout)
+			defer cancelFunc()
+		}
 
-		ldr, err := eval.NewRealLoader(f)
+		w, err := newWaiter(fl, cancelFunc)
 		if err != nil {
-			return fmt.Errorf("Can't create loader: %w", err)
+			return err
 		}
+		info := fl.runInfo(w)
+
+		if fl.dryRun {
+			if fl.multiCluster() {
+				return fmt.Errorf("--dry-run is not supported with multiple clusters")
+			}
+			if len(posArgs) > 0 || len(filenameOpts.Filenames) == 0 {
+				return fmt.Errorf("--dry-run expects the manifests via -f or -")
+			}
+			evaluator, err := fl.newEvaluator(f)
+			if err != nil {
+				return err
+			}
+			printer, err := fl.toPrinter()
+			if err != nil {
+				return fmt.Errorf("Can't create printer: %w", err)
+			}
+			printer = withErrOut(withRunInfo(printer, info), cmd.ErrOrStderr())
+			if err := fl.runDryRun(ctx, evaluator, namespace, explicitNamespace, filenameOpts,
+				printer, cmd.OutOrStdout(), w.failOn); err != nil {
+				return err
+			}
+			checkPrinted(printer)
+			return nil
+		}
+
+		overview, overviewNs := fl.overview, fl.overviewNamespace(namespace)
+
+		var updatesChan <-chan eval.StatusUpdate
+		if fl.multiCluster() {
+			if slices.Contains(filenameOpts.Filenames, "-") {
+				return fmt.Errorf("reading the objects from stdin is not supported with multiple clusters")
+			}
+			kubeContexts, err := fl.kubeContexts()
+			if err != nil {
+				return err
+			}
+			updatesChan = fl.startClusterPollers(ctx, kubeContexts, posArgs, filenameOpts)
+		} else {
+			evaluator, err := fl.newEvaluator(f)
+			if err != nil {
+				return err
+			}
 
-		evaluator := eval.NewEvaluator(analyze.DefaultAnalyzers(), ldr)
+			var poller *eval.StatusPoller
+			if overview {
+				poller = eval.NewQueryStatusPoller(2*time.Second, evaluator,
+					[]eval.QuerySpec{analyze.OverviewQuerySpec(overviewNs)})
+			} else {
+				objects := fl.loadObjects(fl.configFlags, namespace, explicitNamespace, posArgs, filenameOpts)
+				poller = eval.NewStatusPoller(2*time.Second, evaluator, objects)
+			}
+			updatesChan = poller.Start(ctx)
+		}
 
-		poller := eval.NewStatusPoller(2*time.Second, evaluator, objects)
-		updatesChan := poller.Start(ctx)
+		if fl.interactive {
+			return fl.runInteractive(ctx, updatesChan, w.failOn, cmd.Flags().Changed("log-lines"))
+		}
 
 		printer, err := fl.toPrinter()
 		if err != nil {
 			return fmt.Errorf("Can't create printer: %w", err)
 		}
+		if _, isTree := printer.(*print.TreePrinter); isTree && overview {
+			var firstKinds []schema.GroupKind
+			if overviewNs == eval.NamespaceAll {
+				firstKinds = analyze.ClusterOverviewKinds
+			}
+			printer = print.NewOverviewPrinter(fl.printOpts(), firstKinds)
+		}
+		printer = withRunInfo(printer, info)
 
 		outStreams := print.OutStreams{
 			Std: cmd.OutOrStdout(),
 			Err: cmd.ErrOrStderr(),
 		}
+		printer = withErrOut(printer, outStreams.Err)
+
+		if _, streaming := printer.(*print.JSONLinesPrinter); streaming {
+			// Every update is printed, no matter if it's a terminal.
+			print.NewStreamingPrinter(printer, outStreams, updatesChan, w.update).Start()
+		} else if w.waiting() && !stdoutIsTerminal() {
+			// The screen can't be redrawn: log the transitions instead. The log
+			// goes to stderr with structured output to keep stdout parseable.
+			log := outStreams.Std
+			if !strings.HasPrefix(*fl.printFlags.OutputFormat, "tree") {
+				log = outStreams.Err
+			}
+			print.NewTransitionPrinter(printer, outStreams, log, updatesChan, w.update).Start()
+		} else {
+			print.NewPeriodicPrinter(printer, outStreams, updatesChan, w.update).Start()
+		}
 
-		wf := waitFunction(fl, cancelFunc)
-		print.NewPeriodicPrinter(printer, outStreams, updatesChan, wf).Start()
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !w.finished {
+			w.timedOut()
+			fmt.Fprintf(outStreams.Err, "Timed out after %s\n", fl.timeout)
+		}
+		checkPrinted(printer)
 
 		return nil
 	}
 }
 
-// waitFunction decides when to stop waiting for the resources.
-// It's used by the PeriodicPrinter to decide when to stop the loop.
-func waitFunction(fl *flags, cancelFunc func()) func([]status.ObjectStatus) {
-	return func(statuses []status.ObjectStatus) {
-		if fl.waitForever {
-			return
-		}
+func (f *flags) newEvaluator(getter eval.RESTClientGetter) (*eval.Evaluator, error) {
+	ldr, err := eval.NewRealLoader(getter)
+	if err != nil {
+		return nil, fmt.Errorf("Can't create loader: %w", err)
+	}
+	evaluator := eval.NewEvaluator(analyze.DefaultAnalyzers(), ldr)
+	evaluator.SetPodLogOptions(f.podLogOptions())
+	// The interactive details view shows why the verdicts were chosen.
+	evaluator.SetExplain(f.explain || f.interactive)
+	return evaluator, nil
+}
 
-		finish := func() {
-			setExitCode(statuses)
-			cancelFunc()
-		}
+// podLogOptions returns which logs are shown for the unhealthy containers.
+func (f *flags) podLogOptions() eval.PodLogOptions {
+	return eval.PodLogOptions{TailLines: f.logLines, Previous: f.previousLogs}
+}
 
-		progressing := false
-		if fl.waitProgress || fl.waitOk {
-			for _, os := range statuses {
-				// Consider the unknown status as progressing as well.
-				if os.ObjStatus.Progressing || os.ObjStatus.Result == status.Unknown {
-					progressing = true
-				}
-			}
+// applyOverviewTarget switches to the overview mode for the `cluster` and
+// `namespace/<ns>` arguments and returns the remaining arguments. The Namespace
+// object itself can still be evaluated as `namespaces/<ns>` or `ns/<ns>`.
+func (f *flags) applyOverviewTarget(posArgs []string) ([]string, error) {
+	if len(posArgs) != 1 {
+		return posArgs, nil
+	}
+	target := posArgs[0]
+	ns, isNamespace := strings.CutPrefix(target, "namespace/")
+	switch {
+	case target == "cluster":
+		if *f.configFlags.Namespace != "" {
+			return nil, fmt.Errorf("cluster covers all the namespaces: -n can't be used")
 		}
-
-		if fl.waitProgress {
-			if !progressing {
-				finish()
-			}
-			return
+		f.allNamespaces = true
+	case isNamespace && ns != "":
+		if f.allNamespaces {
+			return nil, fmt.Errorf("%s covers a single namespace: -A can't be used", target)
 		}
-
-		if fl.waitOk {
-			if progressing {
-				return
-			}
-
-			ready := true
-			for _, os := range statuses {
-				if os.Status().Result != status.Ok {
-					ready = false
-				}
-			}
-			if ready {
-				finish()
-			}
-			return
+		if current := *f.configFlags.Namespace; current != "" && current != ns {
+			return nil, fmt.Errorf("%s conflicts with the namespace %s given by -n", target, current)
 		}
-
-		finish()
+		*f.configFlags.Namespace = ns
+	default:
+		return posArgs, nil
+	}
+	if len(f.filenames) > 0 {
+		return nil, fmt.Errorf("%s doesn't accept resources from -f", target)
 	}
+	f.overview = true
+	return nil, nil
 }
 
-func setExitCode(statuses []status.ObjectStatus) {
-	exitCode = 0
-	for _, os := range statuses {
-		res := os.Status().Result
-
-		switch res {
-		case status.Unknown:
-			exitCode = 3
-			break
-		case status.Error:
-			exitCode = max(exitCode, 2)
-		case status.Warning:
-			exitCode = max(exitCode, 1)
-		case status.Ok:
-			exitCode = max(exitCode, 0)
+// validateResourceArgs checks the resources are given either via the
+// arguments and flags, or by the overview mode.
+func (f *flags) validateResourceArgs(posArgs []string) error {
+	if f.overview {
+		if len(posArgs) > 0 || len(f.filenames) > 0 {
+			return fmt.Errorf("--overview doesn't accept resources: use -n or -A to choose the namespace")
 		}
+		return nil
+	}
+	if len(posArgs) == 0 && len(f.filenames) == 0 {
+		return fmt.Errorf("no resources specified")
 	}
+	return nil
+}
 
-	for _, os := range statuses {
-		if os.Status().Progressing {
-			// Add 4th bit to the exit code if still progressing
-			exitCode = exitCode | 0b1000
-		}
+// overviewNamespace returns the namespace evaluated in the overview mode:
+// eval.NamespaceAll with --all-namespaces.
+func (f *flags) overviewNamespace(namespace string) string {
+	if f.allNamespaces {
+		return eval.NamespaceAll
 	}
+	return namespace
+}
+
+// loadObjects resolves the objects to evaluate from the arguments and flags.
+func (f *flags) loadObjects(getter genericclioptions.RESTClientGetter, namespace string, explicitNamespace bool,
+	posArgs []string, filenameOpts *resource.FilenameOptions) []*status.Object {
+	objects := make([]*status.Object, 0)
+
+	resource.NewBuilder(getter).
+		Unstructured().
+		NamespaceParam(namespace).DefaultNamespace().AllNamespaces(f.allNamespaces).
+		LabelSelectorParam(f.selector).
+		FieldSelectorParam(f.fieldSelector).
+		ResourceTypeOrNameArgs(true, posArgs...).
+		FilenameParam(explicitNamespace, filenameOpts).
+		Flatten().
+		ContinueOnError().
+		Do().
+		Visit(func(info *resource.Info, err error) error {
+			if err != nil {
+				return err
+			}
+
+			unst, ok := info.Object.(*unstructured.Unstructured)
+			if !ok {
+				return fmt.Errorf("expected *unstructured.Unstructured, got %T", info.Object)
+			}
+
+			obj, err := status.NewObjectFromUnstructured(unst)
+			if err != nil {
+				return err
+			}
+			objects = append(objects, obj)
+			return nil
+		})
+
+	return objects
 }
 
 func PrintVersion() {
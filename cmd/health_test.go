@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/inecas/kube-health/pkg/analyze"
+)
+
+func TestValidatePrintFlags(t *testing.T) {
+	t.Setenv("KUBE_HEALTH_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+
+	tests := []struct {
+		name     string
+		modify   func(f *flags)
+		expected string
+	}{
+		{"defaults", func(f *flags) {}, ""},
+		{"min-result", func(f *flags) { f.minResult = "ok" }, `invalid min-result "ok": expected warning or error`},
+		{"max-depth", func(f *flags) { f.maxDepth = -1 }, "invalid max-depth -1: expected a non-negative number"},
+		{"log-lines", func(f *flags) { f.logLines = 0 }, "invalid log-lines 0: expected a positive number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFlags()
+			f.addFlags(&cobra.Command{})
+			tt.modify(f)
+			err := f.validatePrintFlags()
+			if tt.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPrintOptsColor(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string // explicit --output, if not empty
+		terminal bool
+		noColor  string
+		expected bool
+	}{
+		{name: "terminal", terminal: true, expected: true},
+		{name: "not a terminal", terminal: false, expected: false},
+		{name: "NO_COLOR", terminal: true, noColor: "1", expected: false},
+		{name: "explicit color", output: "tree+color", terminal: false, noColor: "1", expected: true},
+		{name: "explicit no color", output: "tree", terminal: true, expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+			isTerminal := stdoutIsTerminal
+			stdoutIsTerminal = func() bool { return tt.terminal }
+			t.Cleanup(func() { stdoutIsTerminal = isTerminal })
+
+			f := newFlags()
+			cmd := &cobra.Command{}
+			f.addFlags(cmd)
+			if tt.output != "" {
+				assert.NoError(t, cmd.Flags().Set("output", tt.output))
+			}
+			assert.Equal(t, tt.expected, f.printOpts().Color)
+		})
+	}
+}
+
+func TestCheckPrinted(t *testing.T) {
+	t.Setenv("KUBE_HEALTH_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	t.Cleanup(func() { exitCode = 0 })
+
+	f := newFlags()
+	f.addFlags(&cobra.Command{})
+	*f.printFlags.OutputFormat = "go-template={{index .objects 5}}"
+	printer, err := f.toPrinter()
+	require.NoError(t, err)
+	errOut := &strings.Builder{}
+	printer = withErrOut(printer, errOut)
+
+	printer.PrintStatuses(nil, &strings.Builder{})
+	checkPrinted(printer)
+	assert.Equal(t, exitCodeFailure, exitCode)
+	assert.Contains(t, errOut.String(), "Error: can't print the report: ")
+}
+
+func TestApplyOverviewTarget(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		flags         []string
+		remaining     []string
+		overview      bool
+		namespace     string
+		allNamespaces bool
+		expected      string
+	}{
+		{name: "resources", args: []string{"deployment/web"}, remaining: []string{"deployment/web"}},
+		{name: "namespace object", args: []string{"namespaces/my-app"}, remaining: []string{"namespaces/my-app"}},
+		{name: "multiple args", args: []string{"namespace/my-app", "cluster"},
+			remaining: []string{"namespace/my-app", "cluster"}},
+		{name: "namespace", args: []string{"namespace/my-app"}, overview: true, namespace: "my-app"},
+		{name: "namespace with the same -n", args: []string{"namespace/my-app"}, flags: []string{"-n", "my-app"},
+			overview: true, namespace: "my-app"},
+		{name: "cluster", args: []string{"cluster"}, overview: true, allNamespaces: true},
+		{name: "namespace with other -n", args: []string{"namespace/my-app"}, flags: []string{"-n", "other"},
+			expected: "namespace/my-app conflicts with the namespace other given by -n"},
+		{name: "namespace with -A", args: []string{"namespace/my-app"}, flags: []string{"-A"},
+			expected: "namespace/my-app covers a single namespace: -A can't be used"},
+		{name: "cluster with -n", args: []string{"cluster"}, flags: []string{"-n", "other"},
+			expected: "cluster covers all the namespaces: -n can't be used"},
+		{name: "cluster with -f", args: []string{"cluster"}, flags: []string{"-f", "manifests.yaml"},
+			expected: "cluster doesn't accept resources from -f"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFlags()
+			cmd := &cobra.Command{}
+			f.addFlags(cmd)
+			require.NoError(t, cmd.ParseFlags(tt.flags))
+
+			remaining, err := f.applyOverviewTarget(tt.args)
+			if tt.expected != "" {
+				assert.EqualError(t, err, tt.expected)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.remaining, remaining)
+			assert.Equal(t, tt.overview, f.overview)
+			assert.Equal(t, tt.namespace, *f.configFlags.Namespace)
+			assert.Equal(t, tt.allNamespaces, f.allNamespaces)
+			assert.NoError(t, f.validateResourceArgs(remaining))
+		})
+	}
+}
+
+func TestRegisterKindFilters(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{{Version: "v1"}})
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Event"}, meta.RESTScopeNamespace)
+	t.Cleanup(func() {
+		analyze.Register.SetIncludedKinds()
+		analyze.Register.SetExcludedKinds()
+	})
+
+	f := newFlags()
+	f.includeKinds = []string{"pods", "events"}
+	f.excludeKinds = []string{"event"}
+	assert.EqualError(t, f.registerKindFilters(mapper), `kind "events" is both included and excluded`)
+
+	f.excludeKinds = nil
+	assert.NoError(t, f.registerKindFilters(mapper))
+}
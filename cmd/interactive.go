@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/term"
+
+	"github.com/inecas/kube-health/pkg/eval"
+	"github.com/inecas/kube-health/pkg/print"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+// Number of log lines fetched for the logs view of the interactive mode,
+// unless set via --log-lines.
+const interactiveLogLines = 200
+
+func (f *flags) validateInteractive(filenameOpts *resource.FilenameOptions) error {
+	tty := term.TTY{In: os.Stdin, Out: os.Stdout}
+	switch {
+	case f.dryRun:
+		return fmt.Errorf("--interactive is not supported with --dry-run")
+	case slices.Contains(filenameOpts.Filenames, "-"):
+		return fmt.Errorf("--interactive is not supported when reading the objects from stdin")
+	case !tty.IsTerminalIn() || !tty.IsTerminalOut():
+		return fmt.Errorf("--interactive requires a terminal")
+	}
+	switch *f.printFlags.OutputFormat {
+	case "tree", "tree+color":
+	default:
+		return fmt.Errorf("--interactive is not supported with the %q output format", *f.printFlags.OutputFormat)
+	}
+	return nil
+}
+
+// runInteractive shows the updates in the interactive terminal UI until
+// the user quits.
+func (f *flags) runInteractive(ctx context.Context, updatesChan <-chan eval.StatusUpdate,
+	failOn status.Result, logLinesSet bool) error {
+	tty := term.TTY{In: os.Stdin, Out: os.Stdout, Raw: true}
+	size := func() (int, int) {
+		if s := tty.GetSize(); s != nil {
+			return int(s.Width), int(s.Height)
+		}
+		return 80, 24
+	}
+
+	logs := &podLogs{flags: f, loaders: make(map[string]eval.Loader)}
+	printer := print.NewInteractivePrinter(f.printOpts(), os.Stdin, os.Stdout, size, updatesChan,
+		logs.fetch, f.interactiveLogOptions(logLinesSet), func(statuses []status.ObjectStatus) {
+			setExitCode(statuses, failOn)
+		})
+
+	return tty.Safe(func() error {
+		printer.Start(ctx)
+		return nil
+	})
+}
+
+// interactiveLogOptions returns the options for the logs view. The view has
+// room for more lines than the tree output, so --log-lines applies only when
+// set explicitly.
+func (f *flags) interactiveLogOptions(logLinesSet bool) eval.PodLogOptions {
+	opts := f.podLogOptions()
+	if !logLinesSet {
+		opts.TailLines = interactiveLogLines
+	}
+	return opts
+}
+
+// podLogs fetches the pod logs on demand for the interactive mode.
+// The loaders are created lazily per cluster.
+type podLogs struct {
+	flags   *flags
+	mu      sync.Mutex
+	loaders map[string]eval.Loader
+}
+
+func (l *podLogs) fetch(ctx context.Context, cluster string, pod *status.Object, container string,
+	opts eval.PodLogOptions) (string, error) {
+	ldr, err := l.loader(cluster)
+	if err != nil {
+		return "", err
+	}
+	logs, err := ldr.LoadPodLogs(ctx, pod, container, opts)
+	if err != nil {
+		return "", err
+	}
+	return string(logs), nil
+}
+
+func (l *podLogs) loader(cluster string) (eval.Loader, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if ldr, found := l.loaders[cluster]; found {
+		return ldr, nil
+	}
+
+	cf := l.flags.configFlags
+	if cluster != "" {
+		cf = l.flags.configFlagsForContext(cluster)
+	}
+	ldr, err := eval.NewRealLoader(util.NewFactory(cf))
+	if err != nil {
+		return nil, fmt.Errorf("Can't create loader: %w", err)
+	}
+	l.loaders[cluster] = ldr
+	return ldr, nil
+}
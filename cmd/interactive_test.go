@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/resource"
+
+	"github.com/inecas/kube-health/pkg/eval"
+)
+
+func TestValidateInteractive(t *testing.T) {
+	tests := []struct {
+		name      string
+		dryRun    bool
+		filenames []string
+		expected  string
+	}{
+		{"dry-run", true, nil, "--interactive is not supported with --dry-run"},
+		{"stdin", false, []string{"-"}, "--interactive is not supported when reading the objects from stdin"},
+		// The tests don't run in a terminal.
+		{"no terminal", false, []string{"manifests.yaml"}, "--interactive requires a terminal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFlags()
+			f.dryRun = tt.dryRun
+			err := f.validateInteractive(&resource.FilenameOptions{Filenames: tt.filenames})
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
+
+func TestInteractiveLogOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected eval.PodLogOptions
+	}{
+		{"default", nil, eval.PodLogOptions{TailLines: interactiveLogLines}},
+		{"log-lines", []string{"--log-lines=20"}, eval.PodLogOptions{TailLines: 20}},
+		{"previous-logs", []string{"--previous-logs"}, eval.PodLogOptions{TailLines: interactiveLogLines, Previous: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFlags()
+			cmd := &cobra.Command{}
+			f.addFlags(cmd)
+			require.NoError(t, cmd.ParseFlags(tt.args))
+			assert.Equal(t, tt.expected, f.interactiveLogOptions(cmd.Flags().Changed("log-lines")))
+		})
+	}
+}
@@ -15,6 +15,8 @@ import (
 
 	healthcmd "github.com/inecas/kube-health/cmd"
 	"github.com/inecas/kube-health/pkg/analyze"
+	"github.com/inecas/kube-health/pkg/analyze/declarative"
+	"github.com/inecas/kube-health/pkg/analyze/plugin"
 
 	// Extra analyzers for Red Hat related projects.
 	_ "github.com/inecas/kube-health/pkg/analyze/redhat"
@@ -49,6 +51,7 @@ type flags struct {
 	configFile   string
 	configFlags  *genericclioptions.ConfigFlags
 	printOnly    bool
+	contexts     []string
 	interval     int // refresh interval in seconds
 	host         string
 	port         int
@@ -73,6 +76,8 @@ func (f *flags) addFlags(fl *pflag.FlagSet) {
 	fs.IntVarP(&f.interval, "interval", "i", f.interval, "Refresh interval in seconds")
 	fs.StringVar(&f.host, "host", f.host, "Host to bind the server to")
 	fs.IntVar(&f.port, "port", f.port, "Port to bind the server to")
+	fs.StringSliceVar(&f.contexts, "contexts", f.contexts,
+		"Kubeconfig contexts of the clusters to monitor. Overrides the contexts from the config file")
 	fl.AddFlagSet(fs)
 }
 
@@ -95,22 +100,40 @@ func runFunc(fl *flags) func(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
+		for _, path := range cfg.Analyzers {
+			analyzersCfg, err := declarative.LoadFile(path)
+			if err != nil {
+				return err
+			}
+			if err := analyzersCfg.Register(analyze.Register); err != nil {
+				return fmt.Errorf("invalid analyzers config %s: %w", path, err)
+			}
+		}
+
+		plugin.Register(cmd.Context(), analyze.Register, plugin.Dirs(cfg.PluginDirs, cfg.PathPlugins)...)
+
+		for gk, policy := range cfg.AggregationPolicies {
+			analyze.Register.RegisterAggregationPolicy(gk, policy)
+		}
+
 		ctx := cmd.Context()
 		ctx, cancelFunc := context.WithCancel(ctx)
 		defer cancelFunc()
 
-		ldr, err := eval.NewRealLoader(f)
-		if err != nil {
-			return fmt.Errorf("Can't create loader: %w", err)
+		if len(fl.contexts) > 0 {
+			cfg.Contexts = fl.contexts
 		}
 
-		evaluator := eval.NewEvaluator(analyze.DefaultAnalyzers(), ldr)
-
 		interval := time.Duration(fl.interval) * time.Second
-		poller := monitor.NewMonitorPoller(interval, evaluator, cfg)
-
-		klog.V(1).InfoS("starting poller", "interval", interval)
-		updatesChan := poller.Start(ctx)
+		var updatesChan <-chan monitor.TargetsStatusUpdate
+		if len(cfg.Contexts) > 0 {
+			updatesChan, err = fl.startClusterPollers(ctx, interval, cfg)
+		} else {
+			updatesChan, err = startPoller(ctx, f, interval, cfg, "")
+		}
+		if err != nil {
+			return err
+		}
 		dedupUpdatesChan := dedupFilter(updatesChan)
 
 		if fl.printOnly {
@@ -127,6 +150,45 @@ func runFunc(fl *flags) func(cmd *cobra.Command, args []string) error {
 	}
 }
 
+// startClusterPollers starts a poller for every cluster from the config.
+// The updates are combined into one, so that the metrics of all the clusters
+// are exposed together.
+func (fl *flags) startClusterPollers(ctx context.Context, interval time.Duration,
+	cfg monitor.Config) (<-chan monitor.TargetsStatusUpdate, error) {
+	chans := make([]<-chan monitor.TargetsStatusUpdate, 0, len(cfg.Contexts))
+	for _, kubeContext := range cfg.Contexts {
+		cf := healthcmd.ConfigFlagsForContext(fl.configFlags, kubeContext)
+		updatesChan, err := startPoller(ctx, util.NewFactory(cf), interval, cfg, kubeContext)
+		if err != nil {
+			return nil, fmt.Errorf("context %s: %w", kubeContext, err)
+		}
+		chans = append(chans, updatesChan)
+	}
+
+	// The metrics of the clusters are exposed as they get evaluated.
+	return eval.MergeLatest(chans, nil, func(updates []monitor.TargetsStatusUpdate) monitor.TargetsStatusUpdate {
+		var ret monitor.TargetsStatusUpdate
+		for _, u := range updates {
+			ret.Statuses = append(ret.Statuses, u.Statuses...)
+		}
+		return ret
+	}), nil
+}
+
+func startPoller(ctx context.Context, f util.Factory, interval time.Duration,
+	cfg monitor.Config, cluster string) (<-chan monitor.TargetsStatusUpdate, error) {
+	ldr, err := eval.NewRealLoader(f)
+	if err != nil {
+		return nil, fmt.Errorf("Can't create loader: %w", err)
+	}
+
+	evaluator := eval.NewEvaluator(analyze.DefaultAnalyzers(), ldr)
+	poller := monitor.NewClusterMonitorPoller(interval, evaluator, cfg, cluster)
+
+	klog.V(1).InfoS("starting poller", "interval", interval, "cluster", cluster)
+	return poller.Start(ctx), nil
+}
+
 func (fl *flags) printStatus(ctx context.Context, cmd *cobra.Command, updatesChan <-chan eval.StatusUpdate,
 	cancelFunc func()) {
 
@@ -188,6 +250,7 @@ func dedup(update monitor.TargetsStatusUpdate) monitor.TargetsStatusUpdate {
 
 		targetStatuses = append(targetStatuses, monitor.TargetStatuses{
 			Target:   target.Target,
+			Cluster:  target.Cluster,
 			Statuses: statuses,
 		})
 	}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/kubectl/pkg/cmd/util"
+
+	"github.com/inecas/kube-health/pkg/analyze"
+	"github.com/inecas/kube-health/pkg/eval"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+// multiCluster returns true if multiple clusters are to be evaluated.
+func (f *flags) multiCluster() bool {
+	return len(f.contexts) > 0 || f.allContexts
+}
+
+// kubeContexts returns the kubeconfig contexts selected via the --contexts
+// or --all-contexts flags.
+func (f *flags) kubeContexts() ([]string, error) {
+	if !f.allContexts {
+		return f.contexts, nil
+	}
+
+	rawConfig, err := f.configFlags.ToRawKubeConfigLoader().RawConfig()
+	if err != nil {
+		return nil, err
+	}
+	var ret []string
+	for name := range rawConfig.Contexts {
+		ret = append(ret, name)
+	}
+	slices.Sort(ret)
+	return ret, nil
+}
+
+// configFlagsForContext returns the config flags with the kubeconfig context
+// overridden.
+func (f *flags) configFlagsForContext(kubeContext string) *genericclioptions.ConfigFlags {
+	return ConfigFlagsForContext(f.configFlags, kubeContext)
+}
+
+// ConfigFlagsForContext returns a copy of the config flags (including
+// the authentication, impersonation and TLS options) with the kubeconfig
+// context overridden. The struct can't be copied as a whole, as it caches
+// the client config for the original context.
+func ConfigFlagsForContext(cf *genericclioptions.ConfigFlags, kubeContext string) *genericclioptions.ConfigFlags {
+	ret := genericclioptions.NewConfigFlags(true)
+	ret.CacheDir = cf.CacheDir
+	ret.KubeConfig = cf.KubeConfig
+	ret.ClusterName = cf.ClusterName
+	ret.AuthInfoName = cf.AuthInfoName
+	ret.Context = &kubeContext
+	ret.Namespace = cf.Namespace
+	ret.APIServer = cf.APIServer
+	ret.TLSServerName = cf.TLSServerName
+	ret.Insecure = cf.Insecure
+	ret.CertFile = cf.CertFile
+	ret.KeyFile = cf.KeyFile
+	ret.CAFile = cf.CAFile
+	ret.BearerToken = cf.BearerToken
+	ret.Impersonate = cf.Impersonate
+	ret.ImpersonateUID = cf.ImpersonateUID
+	ret.ImpersonateGroup = cf.ImpersonateGroup
+	ret.ImpersonateUserExtra = cf.ImpersonateUserExtra
+	ret.Username = cf.Username
+	ret.Password = cf.Password
+	ret.Timeout = cf.Timeout
+	ret.DisableCompression = cf.DisableCompression
+	ret.WrapConfigFn = cf.WrapConfigFn
+	return ret
+}
+
+// startClusterPollers evaluates the resources in all the clusters in parallel.
+// The updates are combined into one, with the statuses grouped per cluster
+// (see analyze.ClusterStatus).
+func (f *flags) startClusterPollers(ctx context.Context, kubeContexts []string, posArgs []string,
+	filenameOpts *resource.FilenameOptions) <-chan eval.StatusUpdate {
+	chans := make([]<-chan eval.StatusUpdate, 0, len(kubeContexts))
+	for _, kubeContext := range kubeContexts {
+		chans = append(chans, f.startClusterPoller(ctx, kubeContext, posArgs, filenameOpts))
+	}
+
+	return mergeClusterUpdates(chans, kubeContexts)
+}
+
+// mergeClusterUpdates combines the updates from the clusters. The clusters
+// still being evaluated are shown as pending.
+func mergeClusterUpdates(chans []<-chan eval.StatusUpdate, kubeContexts []string) <-chan eval.StatusUpdate {
+	pending := func(i int) eval.StatusUpdate {
+		return eval.StatusUpdate{Statuses: []status.ObjectStatus{analyze.PendingClusterStatus(kubeContexts[i])}}
+	}
+	return eval.MergeLatest(chans, pending, func(updates []eval.StatusUpdate) eval.StatusUpdate {
+		var ret eval.StatusUpdate
+		for _, u := range updates {
+			ret.Statuses = append(ret.Statuses, u.Statuses...)
+			// The clusters are evaluated in parallel.
+			ret.Duration = max(ret.Duration, u.Duration)
+		}
+		return ret
+	})
+}
+
+// startClusterPoller starts polling the resources in the cluster. Every update
+// contains a single status for the whole cluster.
+func (f *flags) startClusterPoller(ctx context.Context, kubeContext string, posArgs []string,
+	filenameOpts *resource.FilenameOptions) <-chan eval.StatusUpdate {
+	out := make(chan eval.StatusUpdate)
+	go func() {
+		defer close(out)
+
+		send := func(statuses []status.ObjectStatus, err error, duration time.Duration) {
+			select {
+			case out <- eval.StatusUpdate{Statuses: []status.ObjectStatus{
+				analyze.ClusterStatus(kubeContext, statuses, err),
+			}, Duration: duration}:
+			case <-ctx.Done():
+			}
+		}
+
+		poller, err := f.clusterPoller(kubeContext, posArgs, filenameOpts)
+		if err != nil {
+			send(nil, err, 0)
+			return
+		}
+		for update := range poller.Start(ctx) {
+			send(update.Statuses, update.Error, update.Duration)
+		}
+	}()
+	return out
+}
+
+func (f *flags) clusterPoller(kubeContext string, posArgs []string,
+	filenameOpts *resource.FilenameOptions) (*eval.StatusPoller, error) {
+	cf := f.configFlagsForContext(kubeContext)
+	factory := util.NewFactory(cf)
+	evaluator, err := f.newEvaluator(factory)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace, explicitNamespace, err := factory.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return nil, err
+	}
+	if f.overview {
+		return eval.NewQueryStatusPoller(2*time.Second, evaluator,
+			[]eval.QuerySpec{analyze.OverviewQuerySpec(f.overviewNamespace(namespace))}), nil
+	}
+	objects := f.loadObjects(cf, namespace, explicitNamespace, posArgs, filenameOpts)
+	return eval.NewStatusPoller(2*time.Second, evaluator, objects), nil
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/inecas/kube-health/pkg/analyze"
+	"github.com/inecas/kube-health/pkg/eval"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+func TestConfigFlagsForContext(t *testing.T) {
+	cf := genericclioptions.NewConfigFlags(true)
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	cf.AddFlags(fs)
+	require.NoError(t, fs.Parse([]string{
+		"--kubeconfig=/tmp/kubeconfig", "--context=default", "--namespace=my-app",
+		"--as=admin", "--as-group=ops", "--token=secret", "--user=ci",
+		"--request-timeout=5s", "--insecure-skip-tls-verify", "--certificate-authority=/tmp/ca.crt",
+	}))
+
+	ret := ConfigFlagsForContext(cf, "prod")
+	assert.Equal(t, "prod", *ret.Context)
+	assert.Equal(t, "default", *cf.Context)
+
+	// All the other options are kept.
+	expected, actual := reflect.ValueOf(cf).Elem(), reflect.ValueOf(ret).Elem()
+	for i := 0; i < expected.NumField(); i++ {
+		field := expected.Type().Field(i)
+		if !field.IsExported() || field.Name == "Context" || field.Type.Kind() == reflect.Func {
+			continue
+		}
+		assert.Equal(t, expected.Field(i).Interface(), actual.Field(i).Interface(), field.Name)
+	}
+}
+
+func TestMultiClusterSingleEvaluation(t *testing.T) {
+	w, cancelled := testWaiter(t, func(f *flags) {})
+
+	clusterPoller := func(name string, delay time.Duration) <-chan eval.StatusUpdate {
+		ch := make(chan eval.StatusUpdate)
+		go func() {
+			defer close(ch)
+			time.Sleep(delay)
+			ch <- eval.StatusUpdate{Statuses: []status.ObjectStatus{
+				analyze.ClusterStatus(name, []status.ObjectStatus{testStatus(name+"-app", status.Ok, false)}, nil),
+			}}
+		}()
+		return ch
+	}
+	chans := []<-chan eval.StatusUpdate{clusterPoller("fast", 0), clusterPoller("slow", 50*time.Millisecond)}
+
+	var printed [][]status.ObjectStatus
+	for update := range mergeClusterUpdates(chans, []string{"fast", "slow"}) {
+		printed = append(printed, update.Statuses)
+		if !*cancelled {
+			w.update(update.Statuses)
+		}
+	}
+
+	// The first update with the slow cluster pending doesn't finish the run.
+	require.Len(t, printed, 2)
+	assert.True(t, analyze.HasPendingClusters(printed[0]))
+	assert.True(t, *cancelled)
+	assert.False(t, analyze.HasPendingClusters(w.lastStatuses))
+	assert.Equal(t, []string{"fast-app", "slow-app"}, []string{
+		w.lastStatuses[0].SubStatuses[0].Object.Name, w.lastStatuses[1].SubStatuses[0].Object.Name,
+	})
+	assert.Equal(t, 0, exitCode)
+}
@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/inecas/kube-health/pkg/analyze"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+const (
+	// exitCodeProgressing is added to the exit code if some objects are
+	// still progressing.
+	exitCodeProgressing = 0b1000
+	// exitCodeTimeout is added to the exit code if the wait timed out.
+	exitCodeTimeout = 0b10000
+	// exitCodeFailure is used if the command itself failed, e.g. when the
+	// report couldn't be printed.
+	exitCodeFailure = 128
+)
+
+// waitCondition is a predicate from the --wait-for flag, in the
+// condition=TYPE[=STATUS] format as known from `kubectl wait`.
+type waitCondition struct {
+	condType   string
+	condStatus string
+}
+
+func parseWaitCondition(s string) (waitCondition, error) {
+	pred, value, found := strings.Cut(s, "=")
+	if !found || pred != "condition" || value == "" {
+		return waitCondition{}, fmt.Errorf("invalid wait-for %q: expected condition=TYPE[=STATUS]", s)
+	}
+
+	condType, condStatus, found := strings.Cut(value, "=")
+	if !found {
+		condStatus = "True"
+	}
+	if condType == "" || condStatus == "" {
+		return waitCondition{}, fmt.Errorf("invalid wait-for %q: expected condition=TYPE[=STATUS]", s)
+	}
+	return waitCondition{condType: condType, condStatus: condStatus}, nil
+}
+
+// satisfied returns true if all the objects have the condition in the
+// expected status.
+func (c waitCondition) satisfied(statuses []status.ObjectStatus) bool {
+	for _, os := range analyze.UnwrapClusters(statuses) {
+		found := false
+		for _, cond := range os.Conditions {
+			if strings.EqualFold(cond.Type, c.condType) &&
+				strings.EqualFold(string(cond.Condition.Status), c.condStatus) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (c waitCondition) String() string {
+	return fmt.Sprintf("condition=%s=%s", c.condType, c.condStatus)
+}
+
+// waiter decides when to stop waiting for the resources, based on the
+// wait flags. It's called with every status update.
+type waiter struct {
+	forever    bool
+	progress   bool
+	ok         bool
+	conditions []waitCondition
+	stable     time.Duration
+	failOn     status.Result
+	cancelFunc func()
+
+	stableSince  time.Time
+	finished     bool
+	lastStatuses []status.ObjectStatus
+}
+
+func newWaiter(fl *flags, cancelFunc func()) (*waiter, error) {
+	w := &waiter{
+		forever:    fl.waitForever,
+		progress:   fl.waitProgress,
+		ok:         fl.waitOk,
+		stable:     fl.waitStable,
+		cancelFunc: cancelFunc,
+	}
+
+	for _, s := range fl.waitFor {
+		c, err := parseWaitCondition(s)
+		if err != nil {
+			return nil, err
+		}
+		w.conditions = append(w.conditions, c)
+	}
+
+	switch strings.ToLower(fl.failOn) {
+	case "warning":
+		w.failOn = status.Warning
+	case "error":
+		w.failOn = status.Error
+	default:
+		return nil, fmt.Errorf("invalid fail-on %q: expected warning or error", fl.failOn)
+	}
+
+	// Waiting for stable state without specifying the state means
+	// waiting for the resources to be ready.
+	if w.stable > 0 && !w.progress && !w.ok && len(w.conditions) == 0 {
+		w.ok = true
+	}
+
+	return w, nil
+}
+
+// waiting returns true if more than a single update is expected.
+func (w *waiter) waiting() bool {
+	return w.forever || w.progress || w.ok || len(w.conditions) > 0
+}
+
+// strategy describes the active wait strategy, empty when not waiting.
+func (w *waiter) strategy() string {
+	if !w.waiting() {
+		return ""
+	}
+	var parts []string
+	if w.forever {
+		parts = append(parts, "forever")
+	}
+	if w.progress {
+		parts = append(parts, "progress")
+	}
+	if w.ok {
+		parts = append(parts, "ok")
+	}
+	for _, c := range w.conditions {
+		parts = append(parts, c.String())
+	}
+	ret := strings.Join(parts, ", ")
+	if w.stable > 0 {
+		ret += fmt.Sprintf(" (stable for %s)", w.stable)
+	}
+	return ret
+}
+
+// update is the callback for the periodic printer.
+func (w *waiter) update(statuses []status.ObjectStatus) {
+	w.lastStatuses = statuses
+	if w.forever {
+		return
+	}
+
+	if !w.satisfied(statuses) {
+		w.stableSince = time.Time{}
+		return
+	}
+
+	if w.stable > 0 {
+		if w.stableSince.IsZero() {
+			w.stableSince = time.Now()
+		}
+		if time.Since(w.stableSince) < w.stable {
+			return
+		}
+	}
+
+	w.finished = true
+	setExitCode(statuses, w.failOn)
+	w.cancelFunc()
+}
+
+// timedOut sets the exit code after the timeout expired before the wait
+// finished.
+func (w *waiter) timedOut() {
+	setExitCode(w.lastStatuses, w.failOn)
+	exitCode |= exitCodeTimeout
+}
+
+func (w *waiter) satisfied(statuses []status.ObjectStatus) bool {
+	// Some clusters haven't reported yet: even a single evaluation
+	// needs to cover all of them.
+	if analyze.HasPendingClusters(statuses) {
+		return false
+	}
+
+	progressing := false
+	if w.progress || w.ok {
+		for _, os := range statuses {
+			// Consider the unknown status as progressing as well.
+			if os.ObjStatus.Progressing || os.ObjStatus.Result == status.Unknown {
+				progressing = true
+			}
+		}
+	}
+
+	if (w.progress || w.ok) && progressing {
+		return false
+	}
+
+	if w.ok {
+		for _, os := range statuses {
+			if os.Status().Result != status.Ok {
+				return false
+			}
+		}
+	}
+
+	for _, c := range w.conditions {
+		if !c.satisfied(statuses) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// setExitCode sets the exit code based on the worst result of the statuses.
+// Results better than failOn don't affect the exit code.
+func setExitCode(statuses []status.ObjectStatus, failOn status.Result) {
+	exitCode = 0
+	for _, os := range statuses {
+		res := os.Status().Result
+
+		switch res {
+		case status.Unknown:
+			exitCode = 3
+		case status.Error:
+			exitCode = max(exitCode, 2)
+		case status.Warning:
+			if failOn <= status.Warning {
+				exitCode = max(exitCode, 1)
+			}
+		case status.Ok:
+			exitCode = max(exitCode, 0)
+		}
+	}
+
+	for _, os := range statuses {
+		if os.Status().Progressing {
+			exitCode = exitCode | exitCodeProgressing
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+// testStatus creates a status of a deployment with the conditions in
+// the TYPE=STATUS format.
+func testStatus(name string, result status.Result, progressing bool, conditions ...string) status.ObjectStatus {
+	obj := &status.Object{}
+	obj.Kind = "Deployment"
+	obj.Name = name
+	os := status.ObjectStatus{Object: obj, ObjStatus: status.Status{Result: result, Progressing: progressing}}
+	for _, c := range conditions {
+		c, err := parseWaitCondition("condition=" + c)
+		if err != nil {
+			panic(err)
+		}
+		os.Conditions = append(os.Conditions, status.ConditionStatus{
+			Condition:  &metav1.Condition{Type: c.condType, Status: metav1.ConditionStatus(c.condStatus)},
+			CondStatus: &status.Status{Result: result},
+		})
+	}
+	return os
+}
+
+// testWaiter creates the waiter from the flags set by the modify function.
+// The exit code is reset after the test.
+func testWaiter(t *testing.T, modify func(f *flags)) (*waiter, *bool) {
+	t.Cleanup(func() { exitCode = 0 })
+	f := newFlags()
+	f.addFlags(&cobra.Command{})
+	modify(f)
+	cancelled := false
+	w, err := newWaiter(f, func() { cancelled = true })
+	require.NoError(t, err)
+	return w, &cancelled
+}
+
+func TestParseWaitCondition(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected waitCondition
+		err      bool
+	}{
+		{input: "condition=Available", expected: waitCondition{condType: "Available", condStatus: "True"}},
+		{input: "condition=Degraded=False", expected: waitCondition{condType: "Degraded", condStatus: "False"}},
+		{input: "condition=Ready=", err: true},
+		{input: "condition==True", err: true},
+		{input: "condition=", err: true},
+		{input: "condition", err: true},
+		{input: "delete", err: true},
+		{input: "jsonpath={.status.phase}=Running", err: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			c, err := parseWaitCondition(tc.input)
+			if tc.err {
+				assert.EqualError(t, err, `invalid wait-for "`+tc.input+`": expected condition=TYPE[=STATUS]`)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, c)
+		})
+	}
+}
+
+func TestWaitConditionSatisfied(t *testing.T) {
+	c, err := parseWaitCondition("condition=available")
+	require.NoError(t, err)
+	assert.Equal(t, "condition=available=True", c.String())
+
+	available := testStatus("a", status.Ok, false, "Available=True")
+	unavailable := testStatus("b", status.Error, false, "Available=False")
+	assert.True(t, c.satisfied([]status.ObjectStatus{available}))
+	assert.False(t, c.satisfied([]status.ObjectStatus{available, unavailable}))
+	assert.False(t, c.satisfied([]status.ObjectStatus{testStatus("c", status.Ok, false)}))
+
+	// The objects in the clusters are checked.
+	cluster := status.ObjectStatus{Object: status.NewClusterObject("east"),
+		SubStatuses: []status.ObjectStatus{available, unavailable}}
+	assert.False(t, c.satisfied([]status.ObjectStatus{cluster}))
+}
+
+func TestNewWaiter(t *testing.T) {
+	w, _ := testWaiter(t, func(f *flags) {})
+	assert.False(t, w.waiting())
+	assert.Equal(t, "", w.strategy())
+	assert.Equal(t, status.Warning, w.failOn)
+
+	w, _ = testWaiter(t, func(f *flags) {
+		f.waitProgress = true
+		f.waitFor = []string{"condition=Available", "condition=Degraded=False"}
+		f.failOn = "Error"
+	})
+	assert.True(t, w.waiting())
+	assert.Equal(t, "progress, condition=Available=True, condition=Degraded=False", w.strategy())
+	assert.Equal(t, status.Error, w.failOn)
+
+	// Waiting for a stable state means waiting for the ok state by default.
+	w, _ = testWaiter(t, func(f *flags) { f.waitStable = time.Minute })
+	assert.Equal(t, "ok (stable for 1m0s)", w.strategy())
+
+	f := newFlags()
+	f.addFlags(&cobra.Command{})
+	f.failOn = "unknown"
+	_, err := newWaiter(f, func() {})
+	assert.EqualError(t, err, `invalid fail-on "unknown": expected warning or error`)
+
+	f.failOn = "error"
+	f.waitFor = []string{"ready"}
+	_, err = newWaiter(f, func() {})
+	assert.EqualError(t, err, `invalid wait-for "ready": expected condition=TYPE[=STATUS]`)
+}
+
+func TestWaiterUpdate(t *testing.T) {
+	progressing := testStatus("a", status.Ok, true)
+	unknown := testStatus("b", status.Unknown, false)
+	warning := testStatus("c", status.Warning, false)
+	ok := testStatus("d", status.Ok, false)
+
+	w, cancelled := testWaiter(t, func(f *flags) { f.waitProgress = true })
+	w.update([]status.ObjectStatus{progressing, ok})
+	assert.False(t, *cancelled)
+	// The unknown status is considered progressing.
+	w.update([]status.ObjectStatus{unknown, ok})
+	assert.False(t, *cancelled)
+	w.update([]status.ObjectStatus{warning, ok})
+	assert.True(t, *cancelled)
+	assert.True(t, w.finished)
+	assert.Equal(t, 1, exitCode)
+
+	w, cancelled = testWaiter(t, func(f *flags) { f.waitOk = true })
+	w.update([]status.ObjectStatus{warning, ok})
+	assert.False(t, *cancelled)
+	w.update([]status.ObjectStatus{ok})
+	assert.True(t, *cancelled)
+	assert.Equal(t, 0, exitCode)
+
+	w, cancelled = testWaiter(t, func(f *flags) { f.waitFor = []string{"condition=Available"} })
+	w.update([]status.ObjectStatus{testStatus("e", status.Error, true, "Available=False")})
+	assert.False(t, *cancelled)
+	w.update([]status.ObjectStatus{testStatus("e", status.Error, true, "Available=True")})
+	assert.True(t, *cancelled)
+	assert.Equal(t, 2|exitCodeProgressing, exitCode)
+
+	// Waiting forever never finishes.
+	w, cancelled = testWaiter(t, func(f *flags) { f.waitForever = true })
+	w.update([]status.ObjectStatus{ok})
+	assert.False(t, *cancelled)
+	assert.Equal(t, []status.ObjectStatus{ok}, w.lastStatuses)
+}
+
+func TestWaiterStable(t *testing.T) {
+	ok := testStatus("a", status.Ok, false)
+	warning := testStatus("a", status.Warning, false)
+
+	w, cancelled := testWaiter(t, func(f *flags) { f.waitStable = time.Minute })
+	w.update([]status.ObjectStatus{ok})
+	assert.False(t, *cancelled)
+	assert.False(t, w.stableSince.IsZero())
+
+	// A change resets the stable period.
+	w.update([]status.ObjectStatus{warning})
+	assert.True(t, w.stableSince.IsZero())
+	w.update([]status.ObjectStatus{ok})
+	assert.False(t, *cancelled)
+
+	w.stableSince = time.Now().Add(-time.Minute)
+	w.update([]status.ObjectStatus{ok})
+	assert.True(t, *cancelled)
+	assert.Equal(t, 0, exitCode)
+}
+
+func TestWaiterTimedOut(t *testing.T) {
+	w, cancelled := testWaiter(t, func(f *flags) { f.waitOk = true })
+	w.update([]status.ObjectStatus{testStatus("a", status.Error, true)})
+	assert.False(t, *cancelled)
+
+	w.timedOut()
+	assert.Equal(t, 2+8+16, exitCode)
+}
+
+func TestSetExitCode(t *testing.T) {
+	t.Cleanup(func() { exitCode = 0 })
+
+	tests := []struct {
+		name     string
+		statuses []status.ObjectStatus
+		failOn   status.Result
+		expected int
+	}{
+		{name: "ok", statuses: []status.ObjectStatus{testStatus("a", status.Ok, false)}, failOn: status.Warning, expected: 0},
+		{name: "warning", statuses: []status.ObjectStatus{testStatus("a", status.Warning, false)}, failOn: status.Warning, expected: 1},
+		{name: "warning ignored", statuses: []status.ObjectStatus{testStatus("a", status.Warning, false)}, failOn: status.Error, expected: 0},
+		{name: "error", statuses: []status.ObjectStatus{testStatus("a", status.Warning, false), testStatus("b", status.Error, false)}, failOn: status.Error, expected: 2},
+		{name: "unknown", statuses: []status.ObjectStatus{testStatus("a", status.Unknown, false), testStatus("b", status.Error, false)}, failOn: status.Warning, expected: 3},
+		{name: "progressing", statuses: []status.ObjectStatus{testStatus("a", status.Warning, true)}, failOn: status.Warning, expected: 1 + 8},
+		{name: "progressing ok", statuses: []status.ObjectStatus{testStatus("a", status.Ok, true)}, failOn: status.Warning, expected: 8},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			exitCode = 42
+			setExitCode(tc.statuses, tc.failOn)
+			assert.Equal(t, tc.expected, exitCode)
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package analyze
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+// AggregationPolicy decides how the results of the sub-objects contribute
+// to the result of the parent object.
+//
+// The policy is applied only to the sub-objects: the conditions of the object
+// itself are always aggregated by taking the worst result.
+type AggregationPolicy interface {
+	// Aggregate returns the result based on the sub-objects statuses.
+	Aggregate(subStatuses []status.Status) status.Result
+	String() string
+}
+
+var (
+	// DefaultAggregationPolicy is used for objects without explicit policy.
+	DefaultAggregationPolicy AggregationPolicy = WorstOfPolicy{}
+)
+
+// WorstOfPolicy takes the worst result of the sub-objects.
+type WorstOfPolicy struct{}
+
+func (WorstOfPolicy) Aggregate(subStatuses []status.Status) status.Result {
+	res := status.Unknown
+	for _, s := range subStatuses {
+		res = max(res, s.Result)
+	}
+	return res
+}
+
+func (WorstOfPolicy) String() string {
+	return "worst-of"
+}
+
+// ThresholdPolicy reports Error only when more than Percent of the sub-objects
+// are in Error state. Otherwise, failing sub-objects are reported as Warning.
+type ThresholdPolicy struct {
+	Percent float64
+}
+
+func (p ThresholdPolicy) Aggregate(subStatuses []status.Status) status.Result {
+	failing := 0
+	res := status.Unknown
+	for _, s := range subStatuses {
+		if s.Result == status.Error {
+			failing++
+			continue
+		}
+		res = max(res, s.Result)
+	}
+
+	if failing == 0 {
+		return res
+	}
+
+	if float64(failing)*100/float64(len(subStatuses)) > p.Percent {
+		return status.Error
+	}
+	return status.Warning
+}
+
+func (p ThresholdPolicy) String() string {
+	return fmt.Sprintf("threshold=%s", strconv.FormatFloat(p.Percent, 'f', -1, 64))
+}
+
+// QuorumPolicy reports Error only when fewer than Min sub-objects are Ok.
+// If Min is 0, the majority of the sub-objects is required (e.g. for
+// StatefulSets running quorum-based systems). When the quorum is met,
+// failing sub-objects are reported as Warning.
+type QuorumPolicy struct {
+	Min int
+}
+
+func (p QuorumPolicy) Aggregate(subStatuses []status.Status) status.Result {
+	ok := 0
+	worst := status.Unknown
+	for _, s := range subStatuses {
+		if s.Result == status.Ok {
+			ok++
+		}
+		worst = max(worst, s.Result)
+	}
+
+	quorum := p.Min
+	if quorum == 0 {
+		quorum = len(subStatuses)/2 + 1
+	}
+
+	if ok < quorum {
+		if worst < status.Warning {
+			// Not enough Ok sub-objects, but nothing is failing either.
+			return worst
+		}
+		return status.Error
+	}
+
+	return min(worst, status.Warning)
+}
+
+func (p QuorumPolicy) String() string {
+	if p.Min == 0 {
+		return "quorum"
+	}
+	return fmt.Sprintf("quorum=%d", p.Min)
+}
+
+// AnyReadyPolicy reports Ok when at least one of the sub-objects is Ok.
+// Otherwise, it takes the worst result.
+type AnyReadyPolicy struct{}
+
+func (AnyReadyPolicy) Aggregate(subStatuses []status.Status) status.Result {
+	worst := status.Unknown
+	for _, s := range subStatuses {
+		if s.Result == status.Ok {
+			return status.Ok
+		}
+		worst = max(worst, s.Result)
+	}
+	return worst
+}
+
+func (AnyReadyPolicy) String() string {
+	return "any-ready"
+}
+
+// ParseAggregationPolicy parses the policy from its string representation.
+// Supported values:
+//
+//   - worst-of - the worst result of the sub-objects (default)
+//   - threshold=N - Error if more than N% of the sub-objects fail, Warning otherwise
+//   - quorum - Error if the majority of the sub-objects are not Ok, Warning otherwise
+//   - quorum=N - Error if fewer than N sub-objects are Ok, Warning otherwise
+//   - any-ready - Ok if any of the sub-objects is Ok, the worst result otherwise
+func ParseAggregationPolicy(s string) (AggregationPolicy, error) {
+	name, arg, hasArg := strings.Cut(strings.TrimSpace(s), "=")
+	switch name {
+	case "worst-of":
+		if hasArg {
+			return nil, fmt.Errorf("policy %q doesn't accept arguments", name)
+		}
+		return WorstOfPolicy{}, nil
+	case "threshold":
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(arg, "%"), 64)
+		if err != nil || percent < 0 || percent > 100 {
+			return nil, fmt.Errorf("invalid threshold %q: expected percentage between 0 and 100", arg)
+		}
+		return ThresholdPolicy{Percent: percent}, nil
+	case "quorum":
+		if !hasArg {
+			return QuorumPolicy{}, nil
+		}
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid quorum %q: expected positive number", arg)
+		}
+		return QuorumPolicy{Min: n}, nil
+	case "any-ready":
+		if hasArg {
+			return nil, fmt.Errorf("policy %q doesn't accept arguments", name)
+		}
+		return AnyReadyPolicy{}, nil
+	default:
+		return nil, fmt.Errorf("unknown aggregation policy %q", s)
+	}
+}
+
+// policyFromAnnotation returns the policy set via status.AnnotationAggregation.
+func policyFromAnnotation(obj *status.Object) (AggregationPolicy, *status.Override) {
+	val, found := obj.GetAnnotations()[status.AnnotationAggregation]
+	if !found {
+		return nil, nil
+	}
+
+	policy, err := ParseAggregationPolicy(val)
+	if err != nil {
+		klog.V(2).ErrorS(err, "Invalid annotation value", "annotation", status.AnnotationAggregation, "object", obj)
+		return nil, nil
+	}
+
+	return policy, &status.Override{Annotation: status.AnnotationAggregation, Value: val}
+}
+
+// ignoreConditions neutralizes the conditions listed in status.AnnotationIgnoreConditions:
+// they are reported as Ok, so that they don't contribute to the object result.
+func ignoreConditions(obj *status.Object, conditions []status.ConditionStatus) ([]status.ConditionStatus, *status.Override) {
+	val, found := obj.GetAnnotations()[status.AnnotationIgnoreConditions]
+	if !found {
+		return conditions, nil
+	}
+
+	var matchers []Matcher
+	for _, t := range strings.Split(val, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			matchers = append(matchers, StringMatcher(t))
+		}
+	}
+
+	applied := false
+	ret := make([]status.ConditionStatus, len(conditions))
+	for i, cond := range conditions {
+		ret[i] = cond
+		if cond.Condition == nil || !matchAny(matchers, cond.Type) {
+			continue
+		}
+		ret[i].CondStatus = &status.Status{Result: status.Ok, Status: "Ignored"}
+		applied = true
+	}
+
+	if !applied {
+		return conditions, nil
+	}
+	return ret, &status.Override{Annotation: status.AnnotationIgnoreConditions, Value: val}
+}
+
+// ResultScore returns the score of the status: either the explicitly set one,
+// or the one derived from the result (Ok = 100, Warning = 50, Error = 0).
+// It returns nil for Unknown results.
+func ResultScore(s status.Status) *int {
+	if s.Score != nil {
+		return s.Score
+	}
+
+	var score int
+	switch s.Result {
+	case status.Ok:
+		score = 100
+	case status.Warning:
+		score = 50
+	case status.Error:
+		score = 0
+	default:
+		return nil
+	}
+	return &score
+}
+
+// aggregateScore combines the scores: the lowest score of the conditions
+// is combined with the average score of the sub-objects.
+func aggregateScore(conditions []status.ConditionStatus, subStatuses []status.Status) *int {
+	var ret *int
+	for _, cond := range conditions {
+		if s := ResultScore(cond.Status()); s != nil && (ret == nil || *s < *ret) {
+			score := *s
+			ret = &score
+		}
+	}
+
+	sum, count := 0, 0
+	for _, sub := range subStatuses {
+		if s := ResultScore(sub); s != nil {
+			sum += *s
+			count++
+		}
+	}
+
+	if count > 0 {
+		avg := sum / count
+		if ret == nil || avg < *ret {
+			ret = &avg
+		}
+	}
+
+	return ret
+}
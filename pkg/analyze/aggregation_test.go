@@ -0,0 +1,101 @@
+package analyze_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/inecas/kube-health/pkg/analyze"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+func results(rs ...status.Result) []status.Status {
+	ret := make([]status.Status, len(rs))
+	for i, r := range rs {
+		ret[i] = status.Status{Result: r}
+	}
+	return ret
+}
+
+func repeat(r status.Result, n int) []status.Result {
+	ret := make([]status.Result, n)
+	for i := range ret {
+		ret[i] = r
+	}
+	return ret
+}
+
+func TestAggregationPolicies(t *testing.T) {
+	worst := analyze.WorstOfPolicy{}
+	assert.Equal(t, status.Error, worst.Aggregate(results(status.Ok, status.Error, status.Warning)))
+	assert.Equal(t, status.Ok, worst.Aggregate(results(status.Ok, status.Ok)))
+
+	threshold := analyze.ThresholdPolicy{Percent: 20}
+	oneOfFifty := append(repeat(status.Ok, 49), status.Error)
+	assert.Equal(t, status.Warning, threshold.Aggregate(results(oneOfFifty...)))
+	assert.Equal(t, status.Error, threshold.Aggregate(results(status.Ok, status.Ok, status.Error)))
+	assert.Equal(t, status.Ok, threshold.Aggregate(results(status.Ok, status.Ok)))
+
+	quorum := analyze.QuorumPolicy{}
+	assert.Equal(t, status.Warning, quorum.Aggregate(results(status.Ok, status.Ok, status.Error)))
+	assert.Equal(t, status.Error, quorum.Aggregate(results(status.Ok, status.Error, status.Error)))
+	assert.Equal(t, status.Ok, quorum.Aggregate(results(status.Ok, status.Ok, status.Ok)))
+
+	quorumMin := analyze.QuorumPolicy{Min: 1}
+	assert.Equal(t, status.Warning, quorumMin.Aggregate(results(status.Ok, status.Error, status.Error)))
+}
+
+func TestParseAggregationPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		input    string
+		expected analyze.AggregationPolicy
+	}{
+		{"worst-of", analyze.WorstOfPolicy{}},
+		{"threshold=20", analyze.ThresholdPolicy{Percent: 20}},
+		{"threshold=12.5%", analyze.ThresholdPolicy{Percent: 12.5}},
+		{"quorum", analyze.QuorumPolicy{}},
+		{"quorum=2", analyze.QuorumPolicy{Min: 2}},
+		{"any-ready", analyze.AnyReadyPolicy{}},
+	} {
+		p, err := analyze.ParseAggregationPolicy(tc.input)
+		require.NoError(t, err, tc.input)
+		assert.Equal(t, tc.expected, p)
+		assert.Equal(t, strings.TrimSuffix(tc.input, "%"), p.String())
+	}
+
+	for _, input := range []string{"best-of", "threshold", "threshold=120", "quorum=0", "worst-of=1"} {
+		_, err := analyze.ParseAggregationPolicy(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestAggregateResultPolicy(t *testing.T) {
+	obj := &status.Object{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "example.com/v1", Kind: "Cluster"},
+		ObjectMeta: metav1.ObjectMeta{Name: "c1"},
+	}
+	var subStatuses []status.ObjectStatus
+	for _, r := range append(repeat(status.Ok, 9), status.Error) {
+		subStatuses = append(subStatuses, status.ObjectStatus{ObjStatus: status.Status{Result: r}})
+	}
+	conditions := []status.ConditionStatus{analyze.SyntheticConditionOk("Ready", "")}
+
+	os := analyze.AggregateResult(obj, subStatuses, conditions)
+	assert.Equal(t, status.Error, os.Status().Result)
+	require.NotNil(t, os.Status().Score)
+	assert.Equal(t, 90, *os.Status().Score)
+
+	os = analyze.AggregateResultWithPolicy(analyze.ThresholdPolicy{Percent: 50}, obj, subStatuses, conditions)
+	assert.Equal(t, status.Warning, os.Status().Result)
+
+	// Registered policy takes precedence over the analyzer default.
+	gk := schema.GroupKind{Group: "example.com", Kind: "Cluster"}
+	analyze.Register.RegisterAggregationPolicy(gk, analyze.QuorumPolicy{Min: 10})
+	t.Cleanup(func() { analyze.Register.UnregisterAggregationPolicy(gk) })
+	os = analyze.AggregateResultWithPolicy(analyze.ThresholdPolicy{Percent: 50}, obj, subStatuses, conditions)
+	assert.Equal(t, status.Error, os.Status().Result)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"slices"
+	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -69,7 +70,9 @@ func AnalyzeConditions(conditions []*metav1.Condition, analyzers []ConditionAnal
 		}
 
 		if cs.Condition == nil {
-			ret = append(ret, ConditionStatusUnknown(cond))
+			cs = ConditionStatusUnknown(cond)
+			cs.CondStatus.Provenance = &status.Provenance{Reason: "no condition analyzer matched the condition"}
+			ret = append(ret, cs)
 		} else {
 			ret = append(ret, cs)
 		}
@@ -97,8 +100,39 @@ func FromUnstructured(data map[string]interface{}, obj interface{}) error {
 	return runtime.DefaultUnstructuredConverter.FromUnstructured(data, obj)
 }
 
+// AggregateResult combines the results of the conditions and sub-objects
+// into the status of the object. The sub-objects are aggregated using the
+// policy registered for the object kind (worst-of by default).
 func AggregateResult(obj *status.Object, subStatuses []status.ObjectStatus,
 	conditions []status.ConditionStatus) status.ObjectStatus {
+	return AggregateResultWithPolicy(DefaultAggregationPolicy, obj, subStatuses, conditions)
+}
+
+// AggregateResultWithPolicy works as AggregateResult, using the provided policy
+// for aggregating the sub-objects. It's used by the declarative analyzers
+// to apply the policy from the rule. The policy registered for the object kind
+// (see AnalyzerRegister.RegisterAggregationPolicy) takes precedence, followed
+// by the policy set on the object via status.AnnotationAggregation.
+//
+// Conditions listed in status.AnnotationIgnoreConditions are not considered,
+// neither are the sub-objects filtered out by Register.FilterSubStatuses.
+func AggregateResultWithPolicy(policy AggregationPolicy, obj *status.Object,
+	subStatuses []status.ObjectStatus, conditions []status.ConditionStatus) status.ObjectStatus {
+	subStatuses = Register.FilterSubStatuses(subStatuses)
+	var overrides []status.Override
+	if p, found := Register.AggregationPolicyFor(obj.GroupVersionKind().GroupKind()); found {
+		policy = p
+	}
+	if p, override := policyFromAnnotation(obj); p != nil {
+		policy = p
+		overrides = append(overrides, *override)
+	}
+
+	conditions, override := ignoreConditions(obj, conditions)
+	if override != nil {
+		overrides = append(overrides, *override)
+	}
+
 	res := status.Unknown
 	progressing := false
 
@@ -112,25 +146,88 @@ func AggregateResult(obj *status.Object, subStatuses []status.ObjectStatus,
 		}
 	}
 
+	subResults := make([]status.Status, 0, len(subStatuses))
 	for _, sub := range subStatuses {
 		subst := sub.Status()
-		if subst.Result > res {
-			res = subst.Result
-		}
+		subResults = append(subResults, subst)
 		if subst.Progressing {
 			progressing = true
 		}
 	}
 
+	subRes := status.Unknown
+	if len(subResults) > 0 {
+		subRes = policy.Aggregate(subResults)
+		res = max(res, subRes)
+	}
+
 	return status.ObjectStatus{
 		Object: obj,
 		ObjStatus: status.Status{
 			Result:      res,
 			Progressing: progressing,
-			Status:      res.String()},
+			Status:      res.String(),
+			Score:       aggregateScore(conditions, subResults),
+			Provenance: &status.Provenance{
+				Rule:   fmt.Sprintf("aggregation %s", policy),
+				Reason: explainAggregation(res, subRes, conditions, subStatuses),
+			},
+		},
 		SubStatuses: subStatuses,
 		Conditions:  conditions,
+		Overrides:   overrides,
+	}
+}
+
+// explainAggregation describes which conditions and sub-objects determined
+// the aggregated result.
+func explainAggregation(res, subRes status.Result, conditions []status.ConditionStatus,
+	subStatuses []status.ObjectStatus) string {
+	var causes []string
+	for _, cond := range conditions {
+		if st := cond.Status(); st.Result == res && res > status.Ok {
+			causes = append(causes, fmt.Sprintf("condition %s is %s", cond.Type, res))
+		}
+	}
+	if subRes == res && res > status.Ok {
+		var subs []string
+		for _, sub := range subStatuses {
+			if sub.Status().Result > status.Ok {
+				subs = append(subs, fmt.Sprintf("%s is %s", objectRef(sub.Object), sub.Status().Result))
+			}
+		}
+		causes = append(causes, fmt.Sprintf("sub-objects aggregated to %s (%s)", subRes, strings.Join(subs, ", ")))
+	}
+
+	var progressing []string
+	for _, cond := range conditions {
+		if cond.Status().Progressing {
+			progressing = append(progressing, "condition "+cond.Type)
+		}
+	}
+	for _, sub := range subStatuses {
+		if sub.Status().Progressing {
+			progressing = append(progressing, objectRef(sub.Object))
+		}
+	}
+	if len(progressing) > 0 {
+		causes = append(causes, fmt.Sprintf("progressing: %s", strings.Join(progressing, ", ")))
 	}
+
+	if len(causes) == 0 {
+		if res == status.Ok {
+			return "no condition or sub-object reports a problem"
+		}
+		return "no condition or sub-object determined the result"
+	}
+	return strings.Join(causes, "; ")
+}
+
+func objectRef(obj *status.Object) string {
+	if obj == nil {
+		return "<unknown>"
+	}
+	return fmt.Sprintf("%s/%s", obj.Kind, obj.GetName())
 }
 
 // AlwaysGreenAnalyzer is an analyzer that always returns OK status
@@ -150,8 +247,11 @@ func (a AlwaysGreenAnalyzer) Analyze(ctx context.Context, obj *status.Object) st
 // AnalyzerRegister is a registry of analyzers.
 // It allows to register new analyzers and ignored GroupKinds.
 type AnalyzerRegister struct {
-	analyzerInits []eval.AnalyzerInit
-	ignored       []schema.GroupKind
+	analyzerInits       []eval.AnalyzerInit
+	ignored             []schema.GroupKind
+	included            []schema.GroupKind
+	excluded            []schema.GroupKind
+	aggregationPolicies map[schema.GroupKind]AggregationPolicy
 }
 
 // Register registers new analyzers.
@@ -159,6 +259,12 @@ func (r *AnalyzerRegister) Register(a ...eval.AnalyzerInit) {
 	r.analyzerInits = append(r.analyzerInits, a...)
 }
 
+// RegisterFirst registers new analyzers with precedence over the already
+// registered ones. Used for user-provided analyzers overriding the built-in ones.
+func (r *AnalyzerRegister) RegisterFirst(a ...eval.AnalyzerInit) {
+	r.analyzerInits = append(slices.Clone(a), r.analyzerInits...)
+}
+
 // RegisterSimple registers analyzers without any additional configuration.
 func (r *AnalyzerRegister) RegisterSimple(as ...eval.Analyzer) {
 	for _, a := range as {
@@ -176,6 +282,57 @@ func (r *AnalyzerRegister) RegisterIgnoredKinds(gk ...schema.GroupKind) {
 	r.ignored = append(r.ignored, gk...)
 }
 
+// SetIncludedKinds limits the sub-objects to the given kinds, no matter how
+// they were found. Calling it without arguments removes the limit.
+func (r *AnalyzerRegister) SetIncludedKinds(gk ...schema.GroupKind) {
+	r.included = gk
+}
+
+// SetExcludedKinds hides the sub-objects of the given kinds, no matter how
+// they were found. Calling it without arguments removes the limit.
+func (r *AnalyzerRegister) SetExcludedKinds(gk ...schema.GroupKind) {
+	r.excluded = gk
+}
+
+// FilterSubStatuses drops the sub-objects of the kinds not allowed via
+// SetIncludedKinds and SetExcludedKinds. The synthetic sub-objects (e.g.
+// containers) are kept, as they can't be selected by the kind.
+func (r *AnalyzerRegister) FilterSubStatuses(subStatuses []status.ObjectStatus) []status.ObjectStatus {
+	if len(r.included) == 0 && len(r.excluded) == 0 {
+		return subStatuses
+	}
+	return slices.DeleteFunc(slices.Clone(subStatuses), func(sub status.ObjectStatus) bool {
+		if sub.Object == nil || sub.Object.UID == "" {
+			return false
+		}
+		gk := sub.Object.GroupVersionKind().GroupKind()
+		if len(r.included) > 0 && !slices.Contains(r.included, gk) {
+			return true
+		}
+		return slices.Contains(r.excluded, gk)
+	})
+}
+
+// RegisterAggregationPolicy sets the policy for aggregating sub-objects
+// of the given kind. It takes precedence over the policy passed to
+// AggregateResultWithPolicy.
+func (r *AnalyzerRegister) RegisterAggregationPolicy(gk schema.GroupKind, policy AggregationPolicy) {
+	if r.aggregationPolicies == nil {
+		r.aggregationPolicies = make(map[schema.GroupKind]AggregationPolicy)
+	}
+	r.aggregationPolicies[gk] = policy
+}
+
+// UnregisterAggregationPolicy removes the policy registered for the kind.
+func (r *AnalyzerRegister) UnregisterAggregationPolicy(gk schema.GroupKind) {
+	delete(r.aggregationPolicies, gk)
+}
+
+func (r *AnalyzerRegister) AggregationPolicyFor(gk schema.GroupKind) (AggregationPolicy, bool) {
+	policy, found := r.aggregationPolicies[gk]
+	return policy, found
+}
+
 func (r *AnalyzerRegister) AnalyzerInits() []eval.AnalyzerInit {
 	return r.analyzerInits
 }
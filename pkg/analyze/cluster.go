@@ -0,0 +1,63 @@
+package analyze
+
+import (
+	"time"
+
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+// ClusterStatus groups the statuses evaluated in a cluster under a synthetic
+// Cluster object (see status.NewClusterObject). The cluster gets the worst
+// result of the objects. If the cluster couldn't be evaluated, the error
+// is reported via the Evaluated condition.
+func ClusterStatus(cluster string, statuses []status.ObjectStatus, err error) status.ObjectStatus {
+	obj := status.NewClusterObject(cluster)
+	if err != nil {
+		return AggregateResult(obj, statuses, []status.ConditionStatus{
+			ConditionStatusError(SyntheticCondition("Evaluated", false, "Error", err.Error(), time.Time{})),
+		})
+	}
+	if len(statuses) == 0 {
+		return status.OkStatus(obj, nil)
+	}
+	return AggregateResult(obj, statuses, nil)
+}
+
+// PendingClusterStatus is the status of a cluster that has not been evaluated
+// yet. It's reported as Unknown and progressing via the Evaluated condition.
+func PendingClusterStatus(cluster string) status.ObjectStatus {
+	return AggregateResult(status.NewClusterObject(cluster), nil, []status.ConditionStatus{
+		ConditionStatusProgressing(SyntheticCondition("Evaluated", false, "Pending",
+			"Waiting for the first evaluation", time.Time{})),
+	})
+}
+
+// HasPendingClusters returns true if some of the statuses are clusters
+// not evaluated yet (see PendingClusterStatus).
+func HasPendingClusters(statuses []status.ObjectStatus) bool {
+	for _, s := range statuses {
+		if s.Object == nil || !s.Object.IsCluster() {
+			continue
+		}
+		for _, cond := range s.Conditions {
+			if cond.Type == "Evaluated" && cond.Reason == "Pending" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// UnwrapClusters returns the statuses grouped under the Cluster objects,
+// keeping the other statuses as they are.
+func UnwrapClusters(statuses []status.ObjectStatus) []status.ObjectStatus {
+	var ret []status.ObjectStatus
+	for _, s := range statuses {
+		if s.Object != nil && s.Object.IsCluster() {
+			ret = append(ret, s.SubStatuses...)
+		} else {
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
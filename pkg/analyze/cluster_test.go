@@ -0,0 +1,38 @@
+package analyze_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inecas/kube-health/internal/test"
+	"github.com/inecas/kube-health/pkg/analyze"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+func TestClusterStatus(t *testing.T) {
+	e, _, objs := test.TestEvaluator("nodes.yaml")
+	node := e.Eval(t.Context(), objs[0])
+
+	cs := analyze.ClusterStatus("prod", []status.ObjectStatus{node}, nil)
+	assert.True(t, cs.Object.IsCluster())
+	assert.Equal(t, "prod", cs.Object.Name)
+	assert.Equal(t, node.Status().Result, cs.Status().Result)
+	assert.Equal(t, []status.ObjectStatus{node}, analyze.UnwrapClusters([]status.ObjectStatus{cs}))
+
+	cs = analyze.ClusterStatus("staging", nil, nil)
+	assert.Equal(t, status.Ok, cs.Status().Result)
+
+	cs = analyze.ClusterStatus("dev", nil, errors.New("connection refused"))
+	assert.Equal(t, status.Error, cs.Status().Result)
+	test.AssertConditions(t, "Evaluated Error connection refused (Error)", cs.Conditions)
+
+	cs = analyze.PendingClusterStatus("edge")
+	assert.Equal(t, status.Unknown, cs.Status().Result)
+	assert.True(t, cs.Status().Progressing)
+	test.AssertConditions(t, "Evaluated Pending Waiting for the first evaluation (Unknown)", cs.Conditions)
+
+	assert.True(t, analyze.HasPendingClusters([]status.ObjectStatus{node, cs}))
+	assert.False(t, analyze.HasPendingClusters([]status.ObjectStatus{node, analyze.ClusterStatus("edge", nil, nil)}))
+}
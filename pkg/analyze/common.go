@@ -1,6 +1,7 @@
 package analyze
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 	"time"
@@ -35,6 +36,7 @@ var (
 	// CommonConditionsAnalyzer is a generic condition analyzer that can be used
 	// for any condition type. It's one of the default analyzers.
 	CommonConditionsAnalyzer = GenericConditionAnalyzer{
+		Name:       "CommonConditionsAnalyzer",
 		Conditions: NewStringMatchers("Ready"),
 		ReversedPolarityConditions: append(NewRegexpMatchers("Degraded", "Pressure", "Detected", "Terminating"),
 			NewStringMatchers("Progressing")...),
@@ -73,6 +75,19 @@ func (m StringMatcher) Match(s string) bool {
 	return strings.ToLower(string(m)) == strings.ToLower(s)
 }
 
+func (m StringMatcher) String() string {
+	return string(m)
+}
+
+func matchAny(matchers []Matcher, s string) bool {
+	for _, m := range matchers {
+		if m.Match(s) {
+			return true
+		}
+	}
+	return false
+}
+
 type RegexpMatcher regexp.Regexp
 
 func (m *RegexpMatcher) Match(s string) bool {
@@ -80,6 +95,11 @@ func (m *RegexpMatcher) Match(s string) bool {
 	return len(r.FindStringSubmatch(s)) > 0
 }
 
+func (m *RegexpMatcher) String() string {
+	r := (*regexp.Regexp)(m)
+	return "/" + strings.TrimPrefix(r.String(), "(?i)") + "/"
+}
+
 func NewRegexpMatchers(patterns ...string) []Matcher {
 	matchers := make([]Matcher, len(patterns))
 	for i, pattern := range patterns {
@@ -101,6 +121,9 @@ func NewRegexpMatchers(patterns ...string) []Matcher {
 // `UnknownConditions` or `ProgressingConditions`, in which case the corresponding
 // status is set.
 type GenericConditionAnalyzer struct {
+	// Name identifies the analyzer in the explain output.
+	Name string
+
 	Conditions                 []Matcher
 	ReversedPolarityConditions []Matcher
 	WarningConditions          []Matcher
@@ -108,61 +131,63 @@ type GenericConditionAnalyzer struct {
 	UnknownConditions          []Matcher
 }
 
-func (a GenericConditionAnalyzer) match(condType string) (match, reverse, progressing bool, result status.Result) {
+func (a GenericConditionAnalyzer) match(condType string) (match, reverse, progressing bool, result status.Result, rules []string) {
 	match = false
 	result = status.Unknown
 	progressing = false
 
-	for _, t := range a.Conditions {
-		if t.Match(condType) {
-			match = true
-			result = status.Error
-			break
+	matchRule := func(matchers []Matcher, group string) bool {
+		for _, m := range matchers {
+			if m.Match(condType) {
+				rules = append(rules, fmt.Sprintf("%s %s", group, m))
+				return true
+			}
 		}
+		return false
 	}
 
-	for _, t := range a.ReversedPolarityConditions {
-		if t.Match(condType) {
-			match = true
-			reverse = true
-			// Assigning Error by default, can be further overridden by matchers below.
-			result = status.Error
-			break
-		}
+	if matchRule(a.Conditions, "Conditions") {
+		match = true
+		result = status.Error
 	}
 
-	for _, t := range a.ProgressingConditions {
-		if t.Match(condType) {
-			match = true
-			progressing = true
-			result = status.Unknown
-			break
-		}
+	if matchRule(a.ReversedPolarityConditions, "ReversedPolarityConditions") {
+		match = true
+		reverse = true
+		// Assigning Error by default, can be further overridden by matchers below.
+		result = status.Error
 	}
 
-	for _, t := range a.WarningConditions {
-		if t.Match(condType) {
-			match = true
-			result = status.Warning
-			break
-		}
+	if matchRule(a.ProgressingConditions, "ProgressingConditions") {
+		match = true
+		progressing = true
+		result = status.Unknown
 	}
 
-	for _, t := range a.UnknownConditions {
-		if t.Match(condType) {
-			match = true
-			result = status.Unknown
-			break
-		}
+	if matchRule(a.WarningConditions, "WarningConditions") {
+		match = true
+		result = status.Warning
 	}
 
-	return match, reverse, progressing, result
+	if matchRule(a.UnknownConditions, "UnknownConditions") {
+		match = true
+		result = status.Unknown
+	}
+
+	return match, reverse, progressing, result, rules
+}
+
+func (a GenericConditionAnalyzer) name() string {
+	if a.Name != "" {
+		return a.Name
+	}
+	return "GenericConditionAnalyzer"
 }
 
 func (a GenericConditionAnalyzer) Analyze(cond *metav1.Condition) status.ConditionStatus {
 	res := status.Unknown
 	progressing := false
-	match, reverse, targetProgressing, targetRes := a.match(cond.Type)
+	match, reverse, targetProgressing, targetRes, rules := a.match(cond.Type)
 
 	if !match {
 		return ConditionStatusNoMatch
@@ -183,6 +208,11 @@ func (a GenericConditionAnalyzer) Analyze(cond *metav1.Condition) status.Conditi
 		CondStatus: &status.Status{
 			Result:      res,
 			Progressing: progressing,
+			Provenance: &status.Provenance{
+				Analyzer:         a.name(),
+				Rule:             strings.Join(rules, ", "),
+				ReversedPolarity: reverse,
+			},
 		},
 	}
 }
@@ -265,7 +295,7 @@ func SyntheticCondition(condType string, statusVal bool, reason, message string,
 		Status:             mStatus,
 		Reason:             reason,
 		Message:            message,
-		LastTransitionTime: metav1.Time{lastTransitionTime},
+		LastTransitionTime: metav1.Time{Time: lastTransitionTime},
 	}
 }
 
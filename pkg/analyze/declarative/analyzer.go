@@ -0,0 +1,273 @@
+package declarative
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/inecas/kube-health/pkg/analyze"
+	"github.com/inecas/kube-health/pkg/eval"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+// Analyzer evaluates objects based on a declarative rule.
+type Analyzer struct {
+	e    *eval.Evaluator
+	rule *compiledRule
+}
+
+type compiledRule struct {
+	gk                  schema.GroupKind
+	policy              analyze.AggregationPolicy
+	conditionAnalyzer   analyze.GenericConditionAnalyzer
+	syntheticConditions []compiledCondition
+	subObjects          []SubObjectQuery
+}
+
+type compiledCondition struct {
+	SyntheticCondition
+	result      status.Result
+	progressing bool
+	expression  cel.Program
+	message     cel.Program
+}
+
+func (a *Analyzer) Supports(obj *status.Object) bool {
+	return obj.GroupVersionKind().GroupKind() == a.rule.gk
+}
+
+func (a *Analyzer) Analyze(ctx context.Context, obj *status.Object) status.ObjectStatus {
+	var subStatuses []status.ObjectStatus
+	for _, q := range a.rule.subObjects {
+		s, err := a.e.EvalQuery(ctx, querySpec(obj, q), nil)
+		if err != nil {
+			return status.UnknownStatusWithError(obj, err)
+		}
+		subStatuses = append(subStatuses, s...)
+	}
+
+	conditions := analyze.AnalyzeObservedGeneration(obj)
+
+	conds, err := analyze.AnalyzeObjectConditions(obj, append(
+		[]analyze.ConditionAnalyzer{a.rule.conditionAnalyzer},
+		analyze.DefaultConditionAnalyzers...))
+	if err != nil {
+		return status.UnknownStatusWithError(obj, err)
+	}
+	conditions = append(conditions, conds...)
+
+	for _, c := range a.rule.syntheticConditions {
+		conditions = append(conditions, c.evaluate(obj))
+	}
+
+	return analyze.AggregateResultWithPolicy(a.rule.policy, obj, subStatuses, conditions)
+}
+
+func (c compiledCondition) evaluate(obj *status.Object) status.ConditionStatus {
+	vars := map[string]interface{}{"object": obj.Unstructured.Object}
+
+	out, _, err := c.expression.Eval(vars)
+	if err != nil {
+		return analyze.ConditionStatusUnknownWithError(
+			analyze.SyntheticCondition(c.Type, false, "EvaluationFailed", err.Error(), time.Time{}), err)
+	}
+	ok, isBool := out.Value().(bool)
+	if !isBool {
+		err := fmt.Errorf("expression returned %T, expected bool", out.Value())
+		return analyze.ConditionStatusUnknownWithError(
+			analyze.SyntheticCondition(c.Type, false, "EvaluationFailed", err.Error(), time.Time{}), err)
+	}
+
+	message := c.Message
+	if c.message != nil {
+		if out, _, err := c.message.Eval(vars); err == nil {
+			message = fmt.Sprint(out.Value())
+		} else {
+			message = fmt.Sprintf("Failed to evaluate message: %s", err)
+		}
+	}
+
+	if ok {
+		return analyze.ConditionStatusOk(
+			analyze.SyntheticCondition(c.Type, true, "", message, time.Time{}))
+	}
+	cond := analyze.SyntheticCondition(c.Type, false, c.Reason, message, time.Time{})
+	return status.ConditionStatus{
+		Condition: cond,
+		CondStatus: &status.Status{
+			Result:      c.result,
+			Progressing: c.progressing,
+		},
+	}
+}
+
+func querySpec(obj *status.Object, q SubObjectQuery) eval.QuerySpec {
+	gk := schema.GroupKind{Group: q.Group, Kind: q.Kind}
+	switch q.Type {
+	case QuerySelector:
+		if q.EqualityBased {
+			return eval.NewSelectorLabelEqualityQuerySpecWithPath(obj, gk, splitPath(q.Path)...)
+		}
+		return eval.NewSelectorLabelQuerySpecWithPath(obj, gk, splitPath(q.Path)...)
+	case QueryReference:
+		name, _, _ := unstructured.NestedString(obj.Unstructured.Object, splitPath(q.Path)...)
+		return eval.RefQuerySpec{
+			Object: obj,
+			RefObject: corev1.ObjectReference{
+				APIVersion: schema.GroupVersion{Group: q.Group}.String(),
+				Kind:       q.Kind,
+				Name:       name,
+			},
+		}
+	default:
+		spec := analyze.GenericOwnerQuerySpec(obj)
+		if q.Kind != "" {
+			spec.GK = eval.NewGroupKindMatcherSingle(gk)
+		}
+		return spec
+	}
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "."), ".")
+}
+
+func compile(r Rule) (*compiledRule, error) {
+	if r.Kind == "" {
+		return nil, fmt.Errorf("kind is required")
+	}
+
+	ret := &compiledRule{
+		gk:     schema.GroupKind{Group: r.Group, Kind: r.Kind},
+		policy: analyze.DefaultAggregationPolicy,
+	}
+
+	if r.Aggregation != "" {
+		policy, err := analyze.ParseAggregationPolicy(r.Aggregation)
+		if err != nil {
+			return nil, err
+		}
+		ret.policy = policy
+	}
+
+	cm := r.Conditions
+	for _, values := range [][]string{cm.Normal, cm.Reversed, cm.Warning, cm.Progressing, cm.Unknown} {
+		if err := validateMatchers(values); err != nil {
+			return nil, err
+		}
+	}
+	ret.conditionAnalyzer = analyze.GenericConditionAnalyzer{
+		Name:                       "declarative " + ret.gk.String(),
+		Conditions:                 newMatchers(cm.Normal),
+		ReversedPolarityConditions: newMatchers(cm.Reversed),
+		WarningConditions:          newMatchers(cm.Warning),
+		ProgressingConditions:      newMatchers(cm.Progressing),
+		UnknownConditions:          newMatchers(cm.Unknown),
+	}
+
+	env, err := cel.NewEnv(cel.Variable("object", cel.DynType))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sc := range r.SyntheticConditions {
+		c, err := compileCondition(env, sc)
+		if err != nil {
+			return nil, fmt.Errorf("synthetic condition %s: %w", sc.Type, err)
+		}
+		ret.syntheticConditions = append(ret.syntheticConditions, c)
+	}
+
+	for _, q := range r.SubObjects {
+		if err := validateQuery(q); err != nil {
+			return nil, err
+		}
+	}
+	ret.subObjects = r.SubObjects
+	if len(ret.subObjects) == 0 {
+		ret.subObjects = []SubObjectQuery{{Type: QueryOwner}}
+	}
+
+	return ret, nil
+}
+
+func compileCondition(env *cel.Env, sc SyntheticCondition) (compiledCondition, error) {
+	c := compiledCondition{SyntheticCondition: sc}
+	if sc.Type == "" {
+		return c, fmt.Errorf("type is required")
+	}
+
+	switch strings.ToLower(sc.Result) {
+	case "", "error":
+		c.result = status.Error
+	case "warning":
+		c.result = status.Warning
+	case "progressing":
+		c.result = status.Unknown
+		c.progressing = true
+	case "unknown":
+		c.result = status.Unknown
+	default:
+		return c, fmt.Errorf("unsupported result %q", sc.Result)
+	}
+
+	var err error
+	c.expression, err = compileExpression(env, sc.Expression, cel.BoolType)
+	if err != nil {
+		return c, err
+	}
+
+	if sc.MessageExpression != "" {
+		c.message, err = compileExpression(env, sc.MessageExpression, cel.StringType)
+		if err != nil {
+			return c, err
+		}
+	}
+	return c, nil
+}
+
+func compileExpression(env *cel.Env, expr string, expected *cel.Type) (cel.Program, error) {
+	if expr == "" {
+		return nil, fmt.Errorf("expression is required")
+	}
+	ast, iss := env.Compile(expr)
+	if iss.Err() != nil {
+		return nil, fmt.Errorf("failed to compile %q: %w", expr, iss.Err())
+	}
+	if !ast.OutputType().IsExactType(expected) && !ast.OutputType().IsExactType(cel.DynType) {
+		return nil, fmt.Errorf("expression %q returns %s, expected %s", expr, ast.OutputType(), expected)
+	}
+	return env.Program(ast)
+}
+
+func validateMatchers(values []string) error {
+	for _, v := range values {
+		if len(v) > 1 && strings.HasPrefix(v, "/") && strings.HasSuffix(v, "/") {
+			if _, err := regexp.Compile(v[1 : len(v)-1]); err != nil {
+				return fmt.Errorf("invalid condition matcher %s: %w", v, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateQuery(q SubObjectQuery) error {
+	switch q.Type {
+	case QueryOwner:
+		return nil
+	case QuerySelector, QueryReference:
+		if q.Kind == "" || q.Path == "" {
+			return fmt.Errorf("%s query requires kind and path", q.Type)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported sub-object query type %q", q.Type)
+	}
+}
@@ -0,0 +1,191 @@
+// Package declarative allows defining analyzers in configuration files,
+// without writing Go code.
+//
+// Each rule matches a GroupKind and configures the GenericConditionAnalyzer
+// matchers used for the object conditions. CEL expressions can be used to
+// compute synthetic conditions from the unstructured object, and the sub-objects
+// can be found by owner references, label selectors or references.
+//
+// Example:
+//
+//	analyzers:
+//	- group: example.com
+//	  kind: Widget
+//	  aggregation: threshold=20
+//	  conditions:
+//	    normal: [Ready]
+//	    reversed: [/Degraded$/]
+//	    warning: [/Pressure$/]
+//	    progressing: [Reconciling]
+//	    unknown: [Disabled]
+//	  syntheticConditions:
+//	  - type: ReplicasReady
+//	    expression: object.status.readyReplicas == object.spec.replicas
+//	    result: warning
+//	    messageExpression: "'ready: ' + string(object.status.readyReplicas)"
+//	  subObjects:
+//	  - type: owner
+//	  - type: selector
+//	    kind: Pod
+//	    path: spec.selector
+//	  - type: reference
+//	    kind: ConfigMap
+//	    path: spec.configMapName
+package declarative
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/inecas/kube-health/pkg/analyze"
+	"github.com/inecas/kube-health/pkg/eval"
+)
+
+// Config is the content of the declarative analyzers file.
+type Config struct {
+	Analyzers []Rule `json:"analyzers"`
+}
+
+// Rule defines an analyzer for a single kind.
+type Rule struct {
+	Group string `json:"group"`
+	Kind  string `json:"kind"`
+
+	// Aggregation is the policy for aggregating the sub-objects.
+	// See analyze.ParseAggregationPolicy for supported values.
+	Aggregation string `json:"aggregation,omitempty"`
+
+	// Conditions configures matchers for the conditions in status.conditions.
+	Conditions ConditionMatchers `json:"conditions,omitempty"`
+
+	// SyntheticConditions are computed from the object via CEL expressions.
+	SyntheticConditions []SyntheticCondition `json:"syntheticConditions,omitempty"`
+
+	// SubObjects specifies how to find the sub-objects. If empty, sub-objects
+	// are searched via owner references.
+	SubObjects []SubObjectQuery `json:"subObjects,omitempty"`
+}
+
+// ConditionMatchers maps to analyze.GenericConditionAnalyzer. Each value is
+// matched against the condition type case-insensitively. Values enclosed in
+// slashes (e.g. /Degraded$/) are treated as regular expressions.
+type ConditionMatchers struct {
+	Normal      []string `json:"normal,omitempty"`
+	Reversed    []string `json:"reversed,omitempty"`
+	Warning     []string `json:"warning,omitempty"`
+	Progressing []string `json:"progressing,omitempty"`
+	Unknown     []string `json:"unknown,omitempty"`
+}
+
+// SyntheticCondition is a condition computed by a CEL expression. The object
+// is available in the expression as `object`.
+type SyntheticCondition struct {
+	Type string `json:"type"`
+	// Expression must evaluate to bool: true means the condition is Ok.
+	Expression string `json:"expression"`
+	// Result is used when the expression evaluates to false. One of
+	// error (default), warning, progressing or unknown.
+	Result string `json:"result,omitempty"`
+	// Reason is reported when the expression evaluates to false.
+	Reason string `json:"reason,omitempty"`
+	// Message is a static message of the condition.
+	Message string `json:"message,omitempty"`
+	// MessageExpression is a CEL expression evaluating to string,
+	// used instead of the static Message.
+	MessageExpression string `json:"messageExpression,omitempty"`
+}
+
+// Sub-object query types.
+const (
+	QueryOwner     = "owner"
+	QuerySelector  = "selector"
+	QueryReference = "reference"
+)
+
+// SubObjectQuery specifies how to find the sub-objects.
+type SubObjectQuery struct {
+	// Type is one of owner, selector or reference.
+	Type string `json:"type"`
+
+	// Group and Kind of the sub-objects. Required for selector and reference
+	// queries, optional filter for owner queries.
+	Group string `json:"group,omitempty"`
+	Kind  string `json:"kind,omitempty"`
+
+	// Path is a dot-separated path in the object: to the label selector for
+	// selector queries (e.g. spec.selector), to the name of the referenced
+	// object for reference queries (e.g. spec.configMapName).
+	Path string `json:"path,omitempty"`
+
+	// EqualityBased is set for selectors in the simple key-value form
+	// (e.g. as in Service), rather than matchLabels/matchExpressions.
+	EqualityBased bool `json:"equalityBased,omitempty"`
+}
+
+// LoadFile reads the declarative analyzers configuration.
+func LoadFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Load(data)
+}
+
+// Load parses the declarative analyzers configuration.
+func Load(data []byte) (*Config, error) {
+	var cfg Config
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse analyzers config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// AnalyzerInits compiles the rules into analyzers.
+func (c *Config) AnalyzerInits() ([]eval.AnalyzerInit, error) {
+	ret := make([]eval.AnalyzerInit, 0, len(c.Analyzers))
+	for i, r := range c.Analyzers {
+		compiled, err := compile(r)
+		if err != nil {
+			return nil, fmt.Errorf("analyzer %d (%s): %w", i, r.groupKind(), err)
+		}
+		ret = append(ret, func(e *eval.Evaluator) eval.Analyzer {
+			return &Analyzer{e: e, rule: compiled}
+		})
+	}
+	return ret, nil
+}
+
+// Register compiles the rules and adds the analyzers to the register.
+// They take precedence over the built-in analyzers for the same kinds.
+func (c *Config) Register(r *analyze.AnalyzerRegister) error {
+	inits, err := c.AnalyzerInits()
+	if err != nil {
+		return err
+	}
+	r.RegisterFirst(inits...)
+	return nil
+}
+
+func (r Rule) groupKind() string {
+	if r.Group == "" {
+		return r.Kind
+	}
+	return r.Kind + "." + r.Group
+}
+
+// newMatchers converts the values to matchers: values in /slashes/
+// are turned into regular expressions.
+func newMatchers(values []string) []analyze.Matcher {
+	var ret []analyze.Matcher
+	for _, v := range values {
+		if len(v) > 1 && strings.HasPrefix(v, "/") && strings.HasSuffix(v, "/") {
+			ret = append(ret, analyze.NewRegexpMatchers(v[1:len(v)-1])...)
+		} else {
+			ret = append(ret, analyze.NewStringMatchers(v)...)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,142 @@
+package declarative_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inecas/kube-health/internal/test"
+	"github.com/inecas/kube-health/pkg/analyze"
+	"github.com/inecas/kube-health/pkg/analyze/declarative"
+	"github.com/inecas/kube-health/pkg/eval"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+func TestDeclarativeAnalyzer(t *testing.T) {
+	var os status.ObjectStatus
+
+	e, objs := testEvaluator(t, "analyzers.yaml", "databases.yaml")
+
+	os = e.Eval(t.Context(), objs[0])
+	assert.Equal(t, status.Ok, os.Status().Result)
+	test.AssertConditions(t, `Available   (Ok)
+StorageDegraded   (Ok)
+ReplicasReady  ready: 1 (Ok)`, os.Conditions)
+	require.Len(t, os.SubStatuses, 1)
+	assert.Equal(t, "db-ok-0", os.SubStatuses[0].Object.GetName())
+
+	os = e.Eval(t.Context(), objs[1])
+	assert.Equal(t, status.Error, os.Status().Result)
+	test.AssertConditions(t, `Available   (Ok)
+StorageDegraded DiskFull Disk is full (Error)
+ReplicasReady NotEnoughReplicas ready: 1 (Warning)`, os.Conditions)
+	assert.Empty(t, os.SubStatuses)
+}
+
+func TestDeclarativeInvalidConfig(t *testing.T) {
+	tests := []struct {
+		data     string
+		expected string
+	}{
+		{
+			data:     "analyzers:\n- group: example.com\n",
+			expected: "kind is required",
+		},
+		{
+			data:     "analyzers:\n- kind: Foo\n  conditions:\n    normal: ['/[/']\n",
+			expected: "analyzer 0 (Foo): invalid condition matcher /[/",
+		},
+		{
+			data:     "analyzers:\n- kind: Foo\n  aggregation: best-of\n",
+			expected: `analyzer 0 (Foo): unknown aggregation policy "best-of"`,
+		},
+		{
+			data:     "analyzers:\n- kind: Foo\n  syntheticConditions:\n  - type: Bar\n    expression: 'object.status +'\n",
+			expected: `synthetic condition Bar: failed to compile "object.status +"`,
+		},
+		{
+			data:     "analyzers:\n- kind: Foo\n  syntheticConditions:\n  - type: Bar\n    expression: 'true'\n    result: fatal\n",
+			expected: `synthetic condition Bar: unsupported result "fatal"`,
+		},
+		{
+			data:     "analyzers:\n- kind: Foo\n  syntheticConditions:\n  - expression: 'true'\n",
+			expected: "synthetic condition : type is required",
+		},
+		{
+			data:     "analyzers:\n- kind: Foo\n  syntheticConditions:\n  - type: Bar\n",
+			expected: "synthetic condition Bar: expression is required",
+		},
+		{
+			data:     "analyzers:\n- kind: Foo\n  syntheticConditions:\n  - type: Bar\n    expression: \"'yes'\"\n",
+			expected: `synthetic condition Bar: expression "'yes'" returns string, expected bool`,
+		},
+		{
+			data:     "analyzers:\n- kind: Foo\n  syntheticConditions:\n  - type: Bar\n    expression: 'true'\n    messageExpression: '1 + 1'\n",
+			expected: `synthetic condition Bar: expression "1 + 1" returns int, expected string`,
+		},
+		{
+			data:     "analyzers:\n- kind: Foo\n  subObjects:\n  - type: selector\n",
+			expected: "analyzer 0 (Foo): selector query requires kind and path",
+		},
+		{
+			data:     "analyzers:\n- kind: Foo\n  subObjects:\n  - type: reference\n    kind: ConfigMap\n",
+			expected: "analyzer 0 (Foo): reference query requires kind and path",
+		},
+		{
+			data:     "analyzers:\n- kind: Foo\n  subObjects:\n  - type: parent\n",
+			expected: `analyzer 0 (Foo): unsupported sub-object query type "parent"`,
+		},
+	}
+	for _, tc := range tests {
+		cfg, err := declarative.Load([]byte(tc.data))
+		require.NoError(t, err, tc.data)
+		_, err = cfg.AnalyzerInits()
+		assert.ErrorContains(t, err, tc.expected, tc.data)
+	}
+
+	_, err := declarative.Load([]byte("analyzers:\n- kind: Foo\n  unknownField: true\n"))
+	assert.Error(t, err)
+}
+
+func TestDeclarativeSubObjects(t *testing.T) {
+	e, objs := testEvaluator(t, "widget-analyzers.yaml", "widgets.yaml")
+
+	os := e.Eval(t.Context(), objs[0])
+	var subObjects []string
+	for _, sub := range os.SubStatuses {
+		subObjects = append(subObjects, sub.Object.Kind+"/"+sub.Object.GetName())
+	}
+	// The owned objects are filtered by the kind.
+	assert.Equal(t, []string{"Pod/widget-0", "ConfigMap/widget-config"}, subObjects)
+}
+
+func TestDeclarativeEvaluationErrors(t *testing.T) {
+	e, objs := testEvaluator(t, "widget-analyzers.yaml", "widgets.yaml")
+
+	// The failed expressions lead to unknown conditions with the error.
+	os := e.Eval(t.Context(), objs[4])
+	test.AssertConditions(t, `MissingField EvaluationFailed no such key: readyReplicas (Unknown)
+NotBool EvaluationFailed expression returned int64, expected bool (Unknown)
+BrokenMessage Failed Failed to evaluate message: no such key: missing (Error)`, os.Conditions)
+	assert.ErrorContains(t, os.Conditions[0].Status().Err, "no such key: readyReplicas")
+	assert.EqualError(t, os.Conditions[1].Status().Err, "expression returned int64, expected bool")
+	assert.Equal(t, status.Error, os.Status().Result)
+}
+
+// testEvaluator creates an evaluator with the declarative analyzers from
+// the config and the objects from the data files.
+func testEvaluator(t *testing.T, config string, data ...string) (*eval.Evaluator, []*status.Object) {
+	cfg, err := declarative.LoadFile(filepath.Join("testdata", config))
+	require.NoError(t, err)
+	inits, err := cfg.AnalyzerInits()
+	require.NoError(t, err)
+
+	loader := eval.NewFakeLoader()
+	var objs []*status.Object
+	for _, d := range data {
+		objs = append(objs, test.RegisterTestData(loader, d)...)
+	}
+	return eval.NewEvaluator(append(inits, analyze.DefaultAnalyzers()...), loader), objs
+}
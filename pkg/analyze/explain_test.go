@@ -0,0 +1,65 @@
+package analyze_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inecas/kube-health/internal/test"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+func TestProvenance(t *testing.T) {
+	var os status.ObjectStatus
+	e, _, objs := test.TestEvaluator("nodes.yaml")
+	os = e.Eval(t.Context(), objs[0])
+	assert.Nil(t, os.ObjStatus.Provenance, "provenance is recorded only in the explain mode")
+	for _, cond := range os.Conditions {
+		assert.Nil(t, cond.CondStatus.Provenance)
+	}
+
+	e, _, objs = test.TestEvaluator("pods.yaml")
+	os = e.Eval(t.Context(), objs[1])
+	assert.NotEmpty(t, os.SubStatuses)
+	for _, sub := range os.SubStatuses {
+		assert.Nil(t, sub.ObjStatus.Provenance, "containers don't carry the provenance either")
+		for _, cond := range sub.Conditions {
+			assert.Nil(t, cond.CondStatus.Provenance)
+		}
+	}
+
+	e, _, objs = test.TestEvaluator("pods.yaml")
+	e.SetExplain(true)
+	os = e.Eval(t.Context(), objs[1])
+	assert.NotNil(t, os.SubStatuses[0].ObjStatus.Provenance)
+
+	e, _, objs = test.TestEvaluator("nodes.yaml")
+	e.SetExplain(true)
+	os = e.Eval(t.Context(), objs[0])
+	assert.Equal(t, "analyzer: analyze.NodeAnalyzer; rule: aggregation worst-of; "+
+		"reason: condition Ready is Error; condition Terminating is Error; condition Unschedulable is Error",
+		os.ObjStatus.Provenance.String())
+	assert.Equal(t, "analyzer: CommonConditionsAnalyzer; "+
+		"rule: ReversedPolarityConditions /Pressure/, WarningConditions /Pressure/; "+
+		"reversed polarity (True is unhealthy)",
+		os.Conditions[0].CondStatus.Provenance.String())
+	assert.Equal(t, "analyzer: CommonConditionsAnalyzer; rule: Conditions Ready",
+		os.Conditions[3].CondStatus.Provenance.String())
+	assert.Equal(t, "analyzer: analyze.NodeAnalyzer; reason: synthetic condition",
+		os.Conditions[5].CondStatus.Provenance.String())
+
+	e, _, objs = test.TestEvaluator("deployments.yaml")
+	e.SetExplain(true)
+	os = e.Eval(t.Context(), objs[0])
+	assert.Equal(t, "analyzer: analyze.DeploymentAnalyzer; rule: aggregation worst-of; "+
+		"reason: progressing: condition Progressing", os.ObjStatus.Provenance.String())
+	assert.Equal(t, "analyzer: analyze.DeploymentAnalyzer; reason: no condition analyzer matched the condition",
+		os.Conditions[0].CondStatus.Provenance.String())
+
+	e, _, objs = test.TestEvaluator("overrides.yaml")
+	e.SetExplain(true)
+	os = e.Eval(t.Context(), objs[1])
+	assert.Equal(t, "analyzer: *analyze.GenericAnalyzer; rule: aggregation worst-of; "+
+		"reason: Warning is expected via the kube-health.io/expected-result annotation, "+
+		"reported as Ok (condition MemoryPressure is Warning)", os.ObjStatus.Provenance.String())
+}
@@ -3,6 +3,7 @@ package analyze
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -12,7 +13,8 @@ import (
 )
 
 // GenericAnalyzer is an analyzer is a generic implementation of an analyzer.
-// It evaluates object conditions against conditionsAnalyzers. It also evaluates
+// It evaluates object conditions against conditionsAnalyzers, followed by
+// the common status fields (see AnalyzeStatusFields). It also evaluates
 // the sub-objects based on owner references.
 type GenericAnalyzer struct {
 	e                   *eval.Evaluator
@@ -44,16 +46,26 @@ func (a *GenericAnalyzer) Analyze(ctx context.Context, obj *status.Object) statu
 	}
 
 	conditions = append(conditions, conds...)
+	conditions = append(conditions, AnalyzeStatusFields(obj, conditions)...)
 
 	return AggregateResult(obj, subStatuses, conditions)
 }
 
+// GenericOwnerQuerySpec finds the objects owned by obj. Unless limited
+// via Register.SetIncludedKinds, all kinds except the ignored and excluded
+// ones are included.
 func GenericOwnerQuerySpec(obj *status.Object) eval.OwnerQuerySpec {
+	if len(Register.included) > 0 {
+		return eval.OwnerQuerySpec{
+			Object: obj,
+			GK:     eval.GroupKindMatcher{IncludedKinds: Register.included},
+		}
+	}
 	return eval.OwnerQuerySpec{
 		Object: obj,
 		GK: eval.GroupKindMatcher{
 			IncludeAll:    true,
-			ExcludedKinds: Register.ignored,
+			ExcludedKinds: slices.Concat(Register.ignored, Register.excluded),
 		},
 	}
 }
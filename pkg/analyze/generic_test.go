@@ -0,0 +1,49 @@
+package analyze_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/inecas/kube-health/internal/test"
+	"github.com/inecas/kube-health/pkg/analyze"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+func TestGenericIncludedKinds(t *testing.T) {
+	e, _, objs := test.TestEvaluator("kindfilters.yaml")
+	os := e.Eval(t.Context(), objs[0])
+	assert.Len(t, os.SubStatuses, 2)
+	assert.Equal(t, status.RelationOwner, os.SubStatuses[0].Relation)
+
+	analyze.Register.SetIncludedKinds(schema.GroupKind{Kind: "Pod"})
+	t.Cleanup(func() { analyze.Register.SetIncludedKinds() })
+
+	e, _, objs = test.TestEvaluator("kindfilters.yaml")
+	os = e.Eval(t.Context(), objs[0])
+	require.Len(t, os.SubStatuses, 1)
+	assert.Equal(t, "parent-pod", os.SubStatuses[0].Object.GetName())
+}
+
+func TestKindFilters(t *testing.T) {
+	e, _, objs := test.TestEvaluator("deployments.yaml", "pods.yaml", "replicasets.yaml")
+	os := e.Eval(t.Context(), objs[0])
+	require.Len(t, os.SubStatuses, 1)
+
+	// The filters apply to the sub-objects found by the specific analyzers too.
+	analyze.Register.SetExcludedKinds(schema.GroupKind{Group: "apps", Kind: "ReplicaSet"})
+	t.Cleanup(func() { analyze.Register.SetExcludedKinds() })
+	e, _, objs = test.TestEvaluator("deployments.yaml", "pods.yaml", "replicasets.yaml")
+	os = e.Eval(t.Context(), objs[0])
+	assert.Empty(t, os.SubStatuses)
+
+	// The synthetic sub-objects can't be selected by the kind: they are kept.
+	analyze.Register.SetIncludedKinds(schema.GroupKind{Kind: "Pod"})
+	t.Cleanup(func() { analyze.Register.SetIncludedKinds() })
+	e, _, objs = test.TestEvaluator("pods.yaml")
+	os = e.Eval(t.Context(), objs[1])
+	require.NotEmpty(t, os.SubStatuses)
+	assert.Equal(t, "Container", os.SubStatuses[0].Object.Kind)
+}
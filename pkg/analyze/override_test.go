@@ -0,0 +1,39 @@
+package analyze_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inecas/kube-health/internal/test"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+func TestOverrideAnnotations(t *testing.T) {
+	var os status.ObjectStatus
+	e, _, objs := test.TestEvaluator("overrides.yaml")
+
+	os = e.Eval(t.Context(), objs[0])
+	assert.Equal(t, status.Ok, os.Status().Result)
+	assert.Equal(t, "Ignored", os.Status().Status)
+	assert.Equal(t, []status.Override{{Annotation: status.AnnotationIgnore, Value: "true"}}, os.Overrides)
+
+	os = e.Eval(t.Context(), objs[1])
+	assert.Equal(t, status.Ok, os.Status().Result)
+	assert.Equal(t, []status.Override{{Annotation: status.AnnotationExpectedResult, Value: "warning"}}, os.Overrides)
+
+	os = e.Eval(t.Context(), objs[2])
+	assert.Equal(t, status.Error, os.Status().Result)
+	assert.Empty(t, os.Overrides)
+
+	os = e.Eval(t.Context(), objs[3])
+	assert.Equal(t, status.Ok, os.Status().Result)
+	test.AssertConditions(t, `Ready   (Ok)
+Degraded   (Ok)`, os.Conditions)
+	assert.Equal(t, []status.Override{{Annotation: status.AnnotationIgnoreConditions, Value: "Degraded"}}, os.Overrides)
+
+	os = e.Eval(t.Context(), objs[4])
+	assert.Equal(t, status.Ok, os.Status().Result)
+	assert.Len(t, os.SubStatuses, 2)
+	assert.Equal(t, []status.Override{{Annotation: status.AnnotationAggregation, Value: "any-ready"}}, os.Overrides)
+}
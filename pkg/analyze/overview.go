@@ -0,0 +1,62 @@
+package analyze
+
+import (
+	"slices"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/inecas/kube-health/pkg/eval"
+)
+
+var (
+	// overviewIgnoredGroupKinds are skipped when looking for the top-level
+	// objects, in addition to the ignored kinds. These are kinds that are not
+	// owned by other objects but don't tell much about the health.
+	overviewIgnoredGroupKinds = []schema.GroupKind{
+		{Kind: "Event"},
+		{Kind: "Event", Group: "events.k8s.io"},
+		{Kind: "Endpoints"},
+		{Kind: "Namespace"},
+		{Kind: "Lease", Group: "coordination.k8s.io"},
+		{Kind: "ComponentStatus"},
+		{Kind: "NodeMetrics", Group: "metrics.k8s.io"},
+		{Kind: "PodMetrics", Group: "metrics.k8s.io"},
+		{Kind: "EndpointSlice", Group: "discovery.k8s.io"},
+		{Kind: "ControllerRevision", Group: "apps"},
+		// Configuration and RBAC objects: they are numerous (e.g. kube-root-ca.crt
+		// in every namespace) and have no health on their own. Secrets must not be
+		// read for the overview at all.
+		{Kind: "Secret"},
+		{Kind: "ConfigMap"},
+		{Kind: "ServiceAccount"},
+		{Kind: "Role", Group: "rbac.authorization.k8s.io"},
+		{Kind: "RoleBinding", Group: "rbac.authorization.k8s.io"},
+		{Kind: "ClusterRole", Group: "rbac.authorization.k8s.io"},
+		{Kind: "ClusterRoleBinding", Group: "rbac.authorization.k8s.io"},
+		{Kind: "CustomResourceDefinition", Group: "apiextensions.k8s.io"},
+		{Kind: "MutatingWebhookConfiguration", Group: "admissionregistration.k8s.io"},
+		{Kind: "ValidatingWebhookConfiguration", Group: "admissionregistration.k8s.io"},
+		{Kind: "PriorityClass", Group: "scheduling.k8s.io"},
+	}
+
+	// ClusterOverviewKinds are the kinds shown first in the cluster overview:
+	// their health usually explains issues in the rest of the cluster.
+	ClusterOverviewKinds = []schema.GroupKind{
+		{Kind: "Node"},
+		{Kind: "ClusterOperator", Group: "config.openshift.io"},
+		{Kind: "APIService", Group: "apiregistration.k8s.io"},
+	}
+)
+
+// OverviewQuerySpec returns a query for the top-level objects in the namespace
+// (use eval.NamespaceAll for the whole cluster): objects without owner
+// references, excluding the ignored kinds.
+func OverviewQuerySpec(ns string) eval.TopLevelQuerySpec {
+	return eval.TopLevelQuerySpec{
+		GK: eval.GroupKindMatcher{
+			IncludeAll:    true,
+			ExcludedKinds: slices.Concat(Register.ignored, overviewIgnoredGroupKinds),
+		},
+		Ns: ns,
+	}
+}
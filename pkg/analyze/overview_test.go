@@ -0,0 +1,36 @@
+package analyze_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/inecas/kube-health/internal/test"
+	"github.com/inecas/kube-health/pkg/analyze"
+	"github.com/inecas/kube-health/pkg/eval"
+)
+
+func TestOverviewQuerySpec(t *testing.T) {
+	e, _, _ := test.TestEvaluator("kindfilters.yaml")
+
+	statuses, err := e.EvalQuery(t.Context(), analyze.OverviewQuerySpec("default"), nil)
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, "parent", statuses[0].Object.GetName())
+	assert.Len(t, statuses[0].SubStatuses, 2)
+}
+
+func TestOverviewQuerySpecIgnoredKinds(t *testing.T) {
+	excluded := analyze.OverviewQuerySpec(eval.NamespaceAll).GK.ExcludedKinds
+	for _, gk := range []schema.GroupKind{
+		{Kind: "Secret"},
+		{Kind: "ConfigMap"},
+		{Kind: "ServiceAccount"},
+		{Kind: "ClusterRoleBinding", Group: "rbac.authorization.k8s.io"},
+		{Kind: "CustomResourceDefinition", Group: "apiextensions.k8s.io"},
+	} {
+		assert.Contains(t, excluded, gk)
+	}
+}
@@ -0,0 +1,316 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+
+	"github.com/inecas/kube-health/pkg/analyze"
+	"github.com/inecas/kube-health/pkg/eval"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+// Prefix of the executables considered analyzer plugins.
+const Prefix = "kube-health-analyzer-"
+
+// DefaultTimeout limits a single plugin invocation.
+var DefaultTimeout = 10 * time.Second
+
+// Plugin is an external analyzer executable.
+type Plugin struct {
+	Name    string
+	Path    string
+	Kinds   []schema.GroupKind
+	Timeout time.Duration
+}
+
+// PathDirs returns the directories on PATH. Unlike the plugin directories,
+// they are searched only on request, as they can contain executables
+// unrelated to kube-health.
+func PathDirs() []string {
+	return filepath.SplitList(os.Getenv("PATH"))
+}
+
+// Dirs returns the directories to discover the plugins in: the explicit ones
+// first, then the default plugin directory and, if requested, PATH.
+func Dirs(explicit []string, path bool) []string {
+	dirs := slices.Concat(explicit, []string{DefaultDir()})
+	if path {
+		dirs = append(dirs, PathDirs()...)
+	}
+	return dirs
+}
+
+// DefaultDir returns the user-specific plugin directory
+// (e.g. ~/.config/kube-health/plugins).
+func DefaultDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "kube-health", "plugins")
+}
+
+// Discover finds the plugin executables in the given directories. When
+// a plugin with the same name is present multiple times, the first one wins.
+func Discover(dirs ...string) []string {
+	var ret []string
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if !strings.HasPrefix(name, Prefix) || seen[name] {
+				continue
+			}
+			path := filepath.Join(dir, name)
+			if !isExecutable(path) {
+				continue
+			}
+			seen[name] = true
+			ret = append(ret, path)
+		}
+	}
+	return ret
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	return info.Mode()&0o111 != 0
+}
+
+// Load asks the plugin for the supported kinds.
+func Load(ctx context.Context, path string) (*Plugin, error) {
+	p := &Plugin{
+		Name:    strings.TrimPrefix(filepath.Base(path), Prefix),
+		Path:    path,
+		Timeout: DefaultTimeout,
+	}
+
+	var resp SupportsResponse
+	if err := p.call(ctx, OpSupports, nil, &resp); err != nil {
+		return nil, err
+	}
+	for _, gk := range resp.Kinds {
+		if gk.Kind == "" {
+			return nil, fmt.Errorf("plugin %s: kind is required", p.Name)
+		}
+		p.Kinds = append(p.Kinds, schema.GroupKind{Group: gk.Group, Kind: gk.Kind})
+	}
+	return p, nil
+}
+
+// Supports returns true if the plugin declared support for the kind.
+func (p *Plugin) Supports(gk schema.GroupKind) bool {
+	return slices.Contains(p.Kinds, gk)
+}
+
+// Analyze sends the object to the plugin.
+func (p *Plugin) Analyze(ctx context.Context, obj *status.Object) (*AnalyzeResponse, error) {
+	req := AnalyzeRequest{
+		ProtocolVersion: ProtocolVersion,
+		Object:          obj.Unstructured.Object,
+	}
+	var resp AnalyzeResponse
+	if err := p.call(ctx, OpAnalyze, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (p *Plugin) call(ctx context.Context, op string, req, resp interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, p.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, p.Path, op)
+	if req != nil {
+		data, err := json.Marshal(req)
+		if err != nil {
+			return err
+		}
+		cmd.Stdin = bytes.NewReader(data)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("plugin %s %s failed: %w: %s", p.Name, op, err, msg)
+		}
+		return fmt.Errorf("plugin %s %s failed: %w", p.Name, op, err)
+	}
+
+	if err := json.Unmarshal(stdout.Bytes(), resp); err != nil {
+		return fmt.Errorf("plugin %s %s: invalid response: %w", p.Name, op, err)
+	}
+	return nil
+}
+
+// Analyzer evaluates objects using the plugin.
+type Analyzer struct {
+	e      *eval.Evaluator
+	plugin *Plugin
+}
+
+// Name identifies the plugin in the explain output.
+func (a *Analyzer) Name() string {
+	return "plugin " + a.plugin.Name
+}
+
+func (a *Analyzer) Supports(obj *status.Object) bool {
+	return a.plugin.Supports(obj.GroupVersionKind().GroupKind())
+}
+
+func (a *Analyzer) Analyze(ctx context.Context, obj *status.Object) status.ObjectStatus {
+	resp, err := a.plugin.Analyze(ctx, obj)
+	if err != nil {
+		return status.UnknownStatusWithError(obj, err)
+	}
+
+	var subStatuses []status.ObjectStatus
+	for _, q := range resp.SubObjects {
+		qs, err := querySpec(obj, q)
+		if err != nil {
+			return status.UnknownStatusWithError(obj, fmt.Errorf("plugin %s: %w", a.plugin.Name, err))
+		}
+		s, err := a.e.EvalQuery(ctx, qs, nil)
+		if err != nil {
+			return status.UnknownStatusWithError(obj, err)
+		}
+		subStatuses = append(subStatuses, s...)
+	}
+
+	conditions := make([]status.ConditionStatus, 0, len(resp.Conditions))
+	for _, c := range resp.Conditions {
+		conditions = append(conditions, status.ConditionStatus{
+			Condition: &metav1.Condition{
+				Type:    c.Type,
+				Status:  c.Status,
+				Reason:  c.Reason,
+				Message: c.Message,
+			},
+			CondStatus: &status.Status{
+				Result:      c.Result,
+				Progressing: c.Progressing,
+			},
+		})
+	}
+
+	os := analyze.AggregateResult(obj, subStatuses, conditions)
+	// The plugin result can only make the aggregated one worse, so that the
+	// aggregation policies and the annotations still apply.
+	if resp.Result != nil && *resp.Result > os.ObjStatus.Result {
+		os.ObjStatus.Result = *resp.Result
+		os.ObjStatus.Score = analyze.ResultScore(status.Status{Result: *resp.Result})
+	}
+	os.ObjStatus.Progressing = os.ObjStatus.Progressing || resp.Progressing
+	if resp.Status != "" {
+		os.ObjStatus.Status = resp.Status
+	}
+	return os
+}
+
+func querySpec(obj *status.Object, q SubObjectQuery) (eval.QuerySpec, error) {
+	gk := schema.GroupKind{Group: q.Group, Kind: q.Kind}
+	switch q.Type {
+	case QueryOwner:
+		spec := analyze.GenericOwnerQuerySpec(obj)
+		if q.Kind != "" {
+			spec.GK = eval.NewGroupKindMatcherSingle(gk)
+		}
+		return spec, nil
+	case QuerySelector:
+		if q.Kind == "" || q.Selector == nil {
+			return nil, fmt.Errorf("selector query requires kind and selector")
+		}
+		selector, err := metav1.LabelSelectorAsSelector(q.Selector)
+		if err != nil {
+			return nil, err
+		}
+		if selector.Empty() {
+			// Don't match all the objects by accident.
+			selector = labels.Nothing()
+		}
+		return eval.LabelQuerySpec{
+			Object:   obj,
+			GK:       eval.NewGroupKindMatcherSingle(gk),
+			Selector: selector,
+		}, nil
+	case QueryReference:
+		if q.Kind == "" || q.Name == "" {
+			return nil, fmt.Errorf("reference query requires kind and name")
+		}
+		return eval.RefQuerySpec{
+			Object: obj,
+			RefObject: corev1.ObjectReference{
+				APIVersion: schema.GroupVersion{Group: q.Group}.String(),
+				Kind:       q.Kind,
+				Name:       q.Name,
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported sub-object query type %q", q.Type)
+	}
+}
+
+// AnalyzerInit returns the initializer of the analyzer backed by the plugin.
+func (p *Plugin) AnalyzerInit() eval.AnalyzerInit {
+	return func(e *eval.Evaluator) eval.Analyzer {
+		return &Analyzer{e: e, plugin: p}
+	}
+}
+
+// Register discovers the plugins in the directories and adds them to the
+// register. They take precedence over the built-in analyzers. The plugins
+// are loaded in parallel, the ones failing to load are skipped.
+func Register(ctx context.Context, r *analyze.AnalyzerRegister, dirs ...string) {
+	paths := Discover(dirs...)
+	plugins := make([]*Plugin, len(paths))
+	var wg sync.WaitGroup
+	for i, path := range paths {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p, err := Load(ctx, path)
+			if err != nil {
+				klog.ErrorS(err, "Failed to load analyzer plugin", "path", path)
+				return
+			}
+			klog.V(1).InfoS("Loaded analyzer plugin", "name", p.Name, "kinds", p.Kinds)
+			plugins[i] = p
+		}()
+	}
+	wg.Wait()
+
+	var inits []eval.AnalyzerInit
+	for _, p := range plugins {
+		if p != nil {
+			inits = append(inits, p.AnalyzerInit())
+		}
+	}
+	r.RegisterFirst(inits...)
+}
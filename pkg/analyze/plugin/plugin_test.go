@@ -0,0 +1,78 @@
+package plugin_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/inecas/kube-health/internal/test"
+	"github.com/inecas/kube-health/pkg/analyze"
+	"github.com/inecas/kube-health/pkg/analyze/plugin"
+	"github.com/inecas/kube-health/pkg/eval"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+func TestPluginAnalyzer(t *testing.T) {
+	var os status.ObjectStatus
+	// Only system directories for the shell utilities used by the plugin.
+	t.Setenv("PATH", "/usr/bin:/bin")
+
+	paths := plugin.Discover("testdata")
+	require.Equal(t, []string{filepath.Join("testdata", "kube-health-analyzer-widget")}, paths)
+
+	p, err := plugin.Load(t.Context(), paths[0])
+	require.NoError(t, err)
+	assert.Equal(t, "widget", p.Name)
+	assert.Equal(t, []schema.GroupKind{{Group: "example.com", Kind: "Widget"}}, p.Kinds)
+
+	loader := eval.NewFakeLoader()
+	objs := test.RegisterTestData(loader, "widgets.yaml")
+	e := eval.NewEvaluator(append([]eval.AnalyzerInit{p.AnalyzerInit()}, analyze.DefaultAnalyzers()...), loader)
+
+	os = e.Eval(t.Context(), objs[0])
+	assert.Equal(t, status.Ok, os.Status().Result)
+	assert.True(t, os.Status().Progressing)
+	test.AssertConditions(t, `Ready Starting Widget is starting (Unknown)`, os.Conditions)
+	require.Len(t, os.SubStatuses, 1)
+	assert.Equal(t, "widget-0", os.SubStatuses[0].Object.GetName())
+
+	os = e.Eval(t.Context(), objs[1])
+	assert.Equal(t, status.Unknown, os.Status().Result)
+	require.Error(t, os.Status().Err)
+	assert.Contains(t, os.Status().Err.Error(), "cannot analyze broken widget")
+
+	// The explicit result is combined with the aggregated one.
+	os = e.Eval(t.Context(), objs[3])
+	assert.Equal(t, status.Error, os.Status().Result)
+
+	os = e.Eval(t.Context(), objs[4])
+	assert.Equal(t, status.Warning, os.Status().Result)
+	assert.Equal(t, "Degraded", os.Status().Status)
+
+	// The plugin is named in the explain output.
+	e.SetExplain(true)
+	os = e.Eval(t.Context(), objs[0])
+	assert.Equal(t, "plugin widget", os.ObjStatus.Provenance.Analyzer)
+	assert.Equal(t, "plugin widget", os.Conditions[0].CondStatus.Provenance.Analyzer)
+}
+
+func TestPluginRegister(t *testing.T) {
+	// PATH is searched only when asked for.
+	t.Setenv("PATH", "testdata:/usr/bin:/bin")
+	assert.Empty(t, plugin.Discover())
+	assert.Len(t, plugin.Discover(plugin.PathDirs()...), 1)
+
+	// The default directory is always searched, PATH only on request.
+	configDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+	defaultDir := filepath.Join(configDir, "kube-health", "plugins")
+	assert.Equal(t, []string{"custom", defaultDir}, plugin.Dirs([]string{"custom"}, false))
+	assert.Equal(t, []string{"custom", defaultDir, "testdata", "/usr/bin", "/bin"}, plugin.Dirs([]string{"custom"}, true))
+
+	r := &analyze.AnalyzerRegister{}
+	plugin.Register(t.Context(), r, "testdata", "testdata")
+	assert.Len(t, r.AnalyzerInits(), 1)
+}
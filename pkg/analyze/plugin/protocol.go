@@ -0,0 +1,95 @@
+// Package plugin implements analyzers running as external executables.
+//
+// Similarly to kubectl plugins, any executable named kube-health-analyzer-*
+// found in the plugin directories (or on PATH, when enabled) is considered
+// an analyzer plugin.
+// The plugin is invoked with a single argument specifying the operation:
+//
+//   - `supports`: the plugin writes SupportsResponse to stdout, listing the
+//     kinds it's able to analyze. It's called once when loading the plugin.
+//   - `analyze`: the plugin reads AnalyzeRequest from stdin and writes
+//     AnalyzeResponse to stdout. It's called for every supported object.
+//
+// All messages are encoded in JSON. Non-zero exit code is treated as a failure,
+// with stderr used as the error message.
+package plugin
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+// ProtocolVersion is sent with every request to allow the plugins to detect
+// incompatible changes in the protocol.
+const ProtocolVersion = "v1"
+
+// Operations passed to the plugin as the first argument.
+const (
+	OpSupports = "supports"
+	OpAnalyze  = "analyze"
+)
+
+// Sub-object query types.
+const (
+	QueryOwner     = "owner"
+	QuerySelector  = "selector"
+	QueryReference = "reference"
+)
+
+// SupportsResponse lists the kinds the plugin is able to analyze.
+type SupportsResponse struct {
+	Kinds []GroupKind `json:"kinds"`
+}
+
+type GroupKind struct {
+	Group string `json:"group"`
+	Kind  string `json:"kind"`
+}
+
+// AnalyzeRequest is sent to the plugin for every object to analyze.
+type AnalyzeRequest struct {
+	ProtocolVersion string                 `json:"protocolVersion"`
+	Object          map[string]interface{} `json:"object"`
+}
+
+// AnalyzeResponse is the result of the analysis. The object result is
+// aggregated from the conditions and the sub-objects.
+type AnalyzeResponse struct {
+	// Result is combined with the aggregated result of the object: the worse
+	// of the two is used.
+	Result      *status.Result `json:"result,omitempty"`
+	Progressing bool           `json:"progressing,omitempty"`
+	// Status is a human readable status of the object.
+	Status     string           `json:"status,omitempty"`
+	Conditions []Condition      `json:"conditions,omitempty"`
+	SubObjects []SubObjectQuery `json:"subObjects,omitempty"`
+}
+
+// Condition is a condition of the object together with its evaluation.
+type Condition struct {
+	Type        string                 `json:"type"`
+	Status      metav1.ConditionStatus `json:"status"`
+	Reason      string                 `json:"reason,omitempty"`
+	Message     string                 `json:"message,omitempty"`
+	Result      status.Result          `json:"result"`
+	Progressing bool                   `json:"progressing,omitempty"`
+}
+
+// SubObjectQuery asks kube-health to evaluate related objects and include them
+// as sub-objects.
+type SubObjectQuery struct {
+	// Type is one of owner, selector or reference.
+	Type string `json:"type"`
+
+	// Group and Kind of the sub-objects. Required for selector and reference
+	// queries, optional filter for owner queries.
+	Group string `json:"group,omitempty"`
+	Kind  string `json:"kind,omitempty"`
+
+	// Selector for the selector queries.
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// Name of the referenced object in the same namespace for reference queries.
+	Name string `json:"name,omitempty"`
+}
@@ -122,7 +122,7 @@ func (a PodAnalyzer) analyzeContainer(ctx context.Context, obj *status.Object, c
 	}
 
 	if cond.Status().Result > status.Ok {
-		a.expandWithLogs(ctx, obj, cs.Name, &cond)
+		a.expandWithLogs(ctx, obj, cs, &cond)
 	}
 
 	conditions = append(conditions, cond)
@@ -131,8 +131,14 @@ func (a PodAnalyzer) analyzeContainer(ctx context.Context, obj *status.Object, c
 }
 
 // expandWithLogs loads container logs and appends them to the condition message.
-func (a PodAnalyzer) expandWithLogs(ctx context.Context, obj *status.Object, container string, cond *status.ConditionStatus) {
-	logs, err := a.loadContainerLogs(ctx, obj, container)
+// The first line looking like an error is repeated at the top, so that it's not
+// lost among the other lines.
+func (a PodAnalyzer) expandWithLogs(ctx context.Context, obj *status.Object, cs corev1.ContainerStatus, cond *status.ConditionStatus) {
+	opts := a.e.PodLogOptions()
+	// The previous logs are available only for restarted containers.
+	opts.Previous = opts.Previous && cs.LastTerminationState.Terminated != nil
+
+	logs, err := a.loadContainerLogs(ctx, obj, cs.Name, opts)
 	if err != nil {
 		logs = "Error loading logs: " + err.Error() + "\n"
 	}
@@ -145,14 +151,22 @@ func (a PodAnalyzer) expandWithLogs(ctx context.Context, obj *status.Object, con
 		cond.Message = "\n"
 	}
 
-	cond.Message += "Logs:\n"
+	if line := eval.FirstErrorLogLine(logs); line != "" && err == nil {
+		cond.Message += "Log highlight: " + line + "\n"
+	}
+	if opts.Previous {
+		cond.Message += "Previous logs:\n"
+	} else {
+		cond.Message += "Logs:\n"
+	}
 	cond.Message += logs
 }
 
-func (a PodAnalyzer) loadContainerLogs(ctx context.Context, obj *status.Object, container string) (string, error) {
+func (a PodAnalyzer) loadContainerLogs(ctx context.Context, obj *status.Object, container string, opts eval.PodLogOptions) (string, error) {
 	logobjs, err := a.e.Load(ctx, eval.PodLogQuerySpec{
 		Object:    obj,
 		Container: container,
+		Options:   opts,
 	})
 	if err != nil {
 		return "", err
@@ -3,6 +3,7 @@ package analyze_test
 import (
 	"testing"
 
+	"github.com/inecas/kube-health/pkg/eval"
 	"github.com/inecas/kube-health/pkg/status"
 	"github.com/stretchr/testify/assert"
 
@@ -34,3 +35,19 @@ Line 2
 Line 3
  (Error)`, os.SubStatuses[0].Conditions)
 }
+
+func TestPodAnalyzerLogOptions(t *testing.T) {
+	e, l, objs := test.TestEvaluator("pods.yaml")
+	e.SetPodLogOptions(eval.PodLogOptions{TailLines: 3, Previous: true})
+	l.RegisterPodLogs("default", "p2", "p2c", "Waiting for restart\n")
+	l.RegisterPreviousPodLogs("default", "p2", "p2c",
+		"Line 1\nLine 2\nConnecting to db\npanic: connection refused\nExiting\n")
+
+	os := e.Eval(t.Context(), objs[1])
+	test.AssertConditions(t, `Ready NotReady Log highlight: panic: connection refused
+Previous logs:
+Connecting to db
+panic: connection refused
+Exiting
+ (Error)`, os.SubStatuses[0].Conditions)
+}
@@ -0,0 +1,369 @@
+// Package predict evaluates manifests before they are applied: the objects
+// (usually results of a server-side dry-run apply) are checked against
+// the live state of their dependencies in the cluster.
+//
+// The problems are reported as synthetic conditions:
+//
+//   - ConfigMapsFound, SecretsFound, PersistentVolumeClaimsFound and
+//     ServiceAccountFound for the references from the pod spec.
+//   - SelectorMatchesPods for Services selecting no pods.
+//   - ImagesPullable for images that already fail to be pulled elsewhere
+//     in the cluster. When the pods can't be listed across all namespaces,
+//     only the namespace of the object is checked.
+//
+// When the live objects can't be loaded (e.g. due to missing permissions),
+// the condition is reported as Unknown instead.
+//
+// The objects from the manifests are considered as existing, as they are
+// going to be created together.
+package predict
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/inecas/kube-health/pkg/analyze"
+	"github.com/inecas/kube-health/pkg/eval"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+var (
+	gkConfigMap      = schema.GroupKind{Kind: "ConfigMap"}
+	gkSecret         = schema.GroupKind{Kind: "Secret"}
+	gkPVC            = schema.GroupKind{Kind: "PersistentVolumeClaim"}
+	gkServiceAccount = schema.GroupKind{Kind: "ServiceAccount"}
+	gkPod            = schema.GroupKind{Kind: "Pod"}
+	gkService        = schema.GroupKind{Kind: "Service"}
+
+	// podSpecPaths are the locations of the pod spec in the workload kinds.
+	podSpecPaths = map[schema.GroupKind][]string{
+		{Kind: "Pod"}:                                          {"spec"},
+		{Kind: "ReplicationController"}:                        {"spec", "template", "spec"},
+		{Kind: "Deployment", Group: "apps"}:                    {"spec", "template", "spec"},
+		{Kind: "ReplicaSet", Group: "apps"}:                    {"spec", "template", "spec"},
+		{Kind: "StatefulSet", Group: "apps"}:                   {"spec", "template", "spec"},
+		{Kind: "DaemonSet", Group: "apps"}:                     {"spec", "template", "spec"},
+		{Kind: "Job", Group: "batch"}:                          {"spec", "template", "spec"},
+		{Kind: "CronJob", Group: "batch"}:                      {"spec", "jobTemplate", "spec", "template", "spec"},
+		{Kind: "DeploymentConfig", Group: "apps.openshift.io"}: {"spec", "template", "spec"},
+	}
+
+	// imagePullFailures are the container waiting reasons caused by the image.
+	imagePullFailures = []string{"ErrImagePull", "ImagePullBackOff", "InvalidImageName"}
+)
+
+// Predictor checks the objects from the manifests against the live state
+// loaded via the evaluator.
+type Predictor struct {
+	e        *eval.Evaluator
+	manifest []*status.Object
+}
+
+// NewPredictor creates a predictor for the objects from the manifests.
+func NewPredictor(e *eval.Evaluator, manifest []*status.Object) *Predictor {
+	return &Predictor{e: e, manifest: manifest}
+}
+
+// Predict returns the status of the object with the synthetic conditions
+// describing the expected problems.
+func (p *Predictor) Predict(ctx context.Context, obj *status.Object) status.ObjectStatus {
+	var conditions []status.ConditionStatus
+
+	podSpec, err := p.podSpec(obj)
+	if err != nil {
+		return status.UnknownStatusWithError(obj, err)
+	}
+	if podSpec != nil {
+		conditions = append(conditions, p.checkReferences(ctx, obj, podSpec)...)
+		conditions = append(conditions, p.checkImages(ctx, obj, podSpec)...)
+	}
+
+	if obj.GroupVersionKind().GroupKind() == gkService {
+		conditions = append(conditions, p.checkSelector(ctx, obj)...)
+	}
+
+	if len(conditions) == 0 {
+		// Nothing to check for the object.
+		return status.OkStatus(obj, nil)
+	}
+	return analyze.AggregateResult(obj, nil, conditions)
+}
+
+func (p *Predictor) podSpec(obj *status.Object) (*corev1.PodSpec, error) {
+	path, found := podSpecPaths[obj.GroupVersionKind().GroupKind()]
+	if !found {
+		return nil, nil
+	}
+	data, found, err := unstructured.NestedMap(obj.Unstructured.Object, path...)
+	if err != nil || !found {
+		return nil, err
+	}
+	var spec corev1.PodSpec
+	if err := analyze.FromUnstructured(data, &spec); err != nil {
+		return nil, fmt.Errorf("failed to read pod spec: %w", err)
+	}
+	return &spec, nil
+}
+
+// podTemplateLabels returns the labels of the pods created by the object.
+func podTemplateLabels(obj *status.Object) (map[string]string, bool) {
+	path, found := podSpecPaths[obj.GroupVersionKind().GroupKind()]
+	if !found {
+		return nil, false
+	}
+	if len(path) == 1 {
+		return obj.GetLabels(), true
+	}
+	// Replace the trailing "spec" with the metadata of the template.
+	labelsPath := append(slices.Clone(path[:len(path)-1]), "metadata", "labels")
+	ret, found, err := unstructured.NestedStringMap(obj.Unstructured.Object, labelsPath...)
+	return ret, found && err == nil
+}
+
+// podSpecRefs holds the names of the objects referenced by a pod spec.
+type podSpecRefs struct {
+	configMaps     []string
+	secrets        []string
+	pvcs           []string
+	serviceAccount string
+}
+
+func (r *podSpecRefs) addConfigMap(name string, optional *bool) {
+	if name != "" && (optional == nil || !*optional) && !slices.Contains(r.configMaps, name) {
+		r.configMaps = append(r.configMaps, name)
+	}
+}
+
+func (r *podSpecRefs) addSecret(name string, optional *bool) {
+	if name != "" && (optional == nil || !*optional) && !slices.Contains(r.secrets, name) {
+		r.secrets = append(r.secrets, name)
+	}
+}
+
+func collectRefs(spec *corev1.PodSpec) podSpecRefs {
+	var refs podSpecRefs
+	for _, v := range spec.Volumes {
+		if v.ConfigMap != nil {
+			refs.addConfigMap(v.ConfigMap.Name, v.ConfigMap.Optional)
+		}
+		if v.Secret != nil {
+			refs.addSecret(v.Secret.SecretName, v.Secret.Optional)
+		}
+		if v.PersistentVolumeClaim != nil && !slices.Contains(refs.pvcs, v.PersistentVolumeClaim.ClaimName) {
+			refs.pvcs = append(refs.pvcs, v.PersistentVolumeClaim.ClaimName)
+		}
+		if v.Projected != nil {
+			for _, s := range v.Projected.Sources {
+				if s.ConfigMap != nil {
+					refs.addConfigMap(s.ConfigMap.Name, s.ConfigMap.Optional)
+				}
+				if s.Secret != nil {
+					refs.addSecret(s.Secret.Name, s.Secret.Optional)
+				}
+			}
+		}
+	}
+
+	for _, c := range slices.Concat(spec.InitContainers, spec.Containers) {
+		for _, envFrom := range c.EnvFrom {
+			if envFrom.ConfigMapRef != nil {
+				refs.addConfigMap(envFrom.ConfigMapRef.Name, envFrom.ConfigMapRef.Optional)
+			}
+			if envFrom.SecretRef != nil {
+				refs.addSecret(envFrom.SecretRef.Name, envFrom.SecretRef.Optional)
+			}
+		}
+		for _, env := range c.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if ref := env.ValueFrom.ConfigMapKeyRef; ref != nil {
+				refs.addConfigMap(ref.Name, ref.Optional)
+			}
+			if ref := env.ValueFrom.SecretKeyRef; ref != nil {
+				refs.addSecret(ref.Name, ref.Optional)
+			}
+		}
+	}
+
+	for _, s := range spec.ImagePullSecrets {
+		refs.addSecret(s.Name, nil)
+	}
+
+	// The default service account is created automatically.
+	if spec.ServiceAccountName != "" && spec.ServiceAccountName != "default" {
+		refs.serviceAccount = spec.ServiceAccountName
+	}
+	return refs
+}
+
+func (p *Predictor) checkReferences(ctx context.Context, obj *status.Object, spec *corev1.PodSpec) []status.ConditionStatus {
+	refs := collectRefs(spec)
+	ns := obj.GetNamespace()
+
+	var ret []status.ConditionStatus
+	check := func(condType string, gk schema.GroupKind, names []string) {
+		if len(names) == 0 {
+			return
+		}
+		var missing []string
+		for _, name := range names {
+			found, err := p.exists(ctx, gk, ns, name)
+			if err != nil {
+				ret = append(ret, loadFailedCondition(condType, err))
+				return
+			}
+			if !found {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) == 0 {
+			ret = append(ret, analyze.SyntheticConditionOk(condType, ""))
+			return
+		}
+		ret = append(ret, analyze.ConditionStatusError(analyze.SyntheticCondition(condType, false, "NotFound",
+			fmt.Sprintf("%s not found in namespace %s: %s", gk.Kind, ns, strings.Join(missing, ", ")), time.Time{})))
+	}
+
+	check("ConfigMapsFound", gkConfigMap, refs.configMaps)
+	check("SecretsFound", gkSecret, refs.secrets)
+	check("PersistentVolumeClaimsFound", gkPVC, refs.pvcs)
+	if refs.serviceAccount != "" {
+		check("ServiceAccountFound", gkServiceAccount, []string{refs.serviceAccount})
+	}
+	return ret
+}
+
+// exists checks the object exists in the cluster or in the manifests. The
+// cluster is asked for the single object, not to list the whole kind.
+func (p *Predictor) exists(ctx context.Context, gk schema.GroupKind, ns, name string) (bool, error) {
+	for _, obj := range p.manifest {
+		if obj.GroupVersionKind().GroupKind() == gk && obj.GetNamespace() == ns && obj.GetName() == name {
+			return true, nil
+		}
+	}
+
+	_, err := p.e.Get(ctx, gk, ns, name)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (p *Predictor) load(ctx context.Context, gk schema.GroupKind, ns string) ([]*status.Object, error) {
+	return p.e.Load(ctx, eval.KindQuerySpec{GK: eval.NewGroupKindMatcherSingle(gk), Ns: ns})
+}
+
+// loadFailedCondition reports the condition couldn't be checked as the live
+// objects failed to load.
+func loadFailedCondition(condType string, err error) status.ConditionStatus {
+	return analyze.ConditionStatusUnknownWithError(
+		analyze.SyntheticCondition(condType, false, "LoadFailed", err.Error(), time.Time{}), err)
+}
+
+// checkSelector checks the service selects some pods, either existing or
+// created by the workloads from the manifests.
+func (p *Predictor) checkSelector(ctx context.Context, obj *status.Object) []status.ConditionStatus {
+	selectorMap, found, err := unstructured.NestedStringMap(obj.Unstructured.Object, "spec", "selector")
+	if err != nil || !found || len(selectorMap) == 0 {
+		return nil
+	}
+	selector := labels.SelectorFromSet(selectorMap)
+
+	for _, m := range p.manifest {
+		if m.GetNamespace() != obj.GetNamespace() {
+			continue
+		}
+		if podLabels, found := podTemplateLabels(m); found && selector.Matches(labels.Set(podLabels)) {
+			return []status.ConditionStatus{analyze.SyntheticConditionOk("SelectorMatchesPods", "")}
+		}
+	}
+
+	pods, err := p.load(ctx, gkPod, obj.GetNamespace())
+	if err != nil {
+		return []status.ConditionStatus{loadFailedCondition("SelectorMatchesPods", err)}
+	}
+	for _, pod := range pods {
+		if selector.Matches(labels.Set(pod.GetLabels())) {
+			return []status.ConditionStatus{analyze.SyntheticConditionOk("SelectorMatchesPods", "")}
+		}
+	}
+
+	return []status.ConditionStatus{analyze.ConditionStatusWarning(analyze.SyntheticCondition(
+		"SelectorMatchesPods", false, "NoPods",
+		fmt.Sprintf("No pods match the selector %s", selector), time.Time{}))}
+}
+
+// checkImages looks for pods in the cluster failing to pull the same images.
+func (p *Predictor) checkImages(ctx context.Context, obj *status.Object, spec *corev1.PodSpec) []status.ConditionStatus {
+	var images []string
+	for _, c := range slices.Concat(spec.InitContainers, spec.Containers) {
+		if !slices.Contains(images, c.Image) {
+			images = append(images, c.Image)
+		}
+	}
+	if len(images) == 0 {
+		return nil
+	}
+
+	// Fall back to the namespace of the object when the user is not allowed
+	// to list the pods across all namespaces.
+	var message string
+	pods, err := p.load(ctx, gkPod, eval.NamespaceAll)
+	if err != nil {
+		pods, err = p.load(ctx, gkPod, obj.GetNamespace())
+		if err != nil {
+			return []status.ConditionStatus{loadFailedCondition("ImagesPullable", err)}
+		}
+		message = fmt.Sprintf("Only pods in namespace %s were checked", obj.GetNamespace())
+	}
+
+	var failures []string
+	for _, image := range images {
+		if failure := imageFailure(pods, image); failure != "" {
+			failures = append(failures, failure)
+		}
+	}
+	if len(failures) == 0 {
+		return []status.ConditionStatus{analyze.SyntheticConditionOk("ImagesPullable", message)}
+	}
+	return []status.ConditionStatus{analyze.ConditionStatusWarning(analyze.SyntheticCondition(
+		"ImagesPullable", false, "PullFailing", strings.Join(failures, "; "), time.Time{}))}
+}
+
+// imageFailure returns a description of the first pod failing to pull the
+// image, or an empty string.
+func imageFailure(pods []*status.Object, image string) string {
+	for _, obj := range pods {
+		var pod corev1.Pod
+		if err := analyze.FromUnstructured(obj.Unstructured.Object, &pod); err != nil {
+			continue
+		}
+
+		specImages := make(map[string]string)
+		for _, c := range slices.Concat(pod.Spec.InitContainers, pod.Spec.Containers) {
+			specImages[c.Name] = c.Image
+		}
+		for _, cs := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses) {
+			if specImages[cs.Name] != image || cs.State.Waiting == nil ||
+				!slices.Contains(imagePullFailures, cs.State.Waiting.Reason) {
+				continue
+			}
+			return fmt.Sprintf("Image %s fails to pull in pod %s/%s (%s)",
+				image, pod.Namespace, pod.Name, cs.State.Waiting.Reason)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,91 @@
+package predict_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/inecas/kube-health/internal/test"
+	"github.com/inecas/kube-health/pkg/analyze/predict"
+	"github.com/inecas/kube-health/pkg/eval"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+func loadManifests(t *testing.T) []*status.Object {
+	list, err := test.LoadObject[unstructured.UnstructuredList]("manifests.yaml")
+	require.NoError(t, err)
+
+	var ret []*status.Object
+	for i := range list.Items {
+		obj, err := status.NewObjectFromUnstructured(&list.Items[i])
+		require.NoError(t, err)
+		ret = append(ret, obj)
+	}
+	return ret
+}
+
+func TestPredict(t *testing.T) {
+	var os status.ObjectStatus
+	e, _, _ := test.TestEvaluator("live.yaml")
+	manifests := loadManifests(t)
+	p := predict.NewPredictor(e, manifests)
+
+	os = p.Predict(t.Context(), manifests[0])
+	assert.Equal(t, status.Error, os.Status().Result)
+	test.AssertConditions(t, `ConfigMapsFound NotFound ConfigMap not found in namespace default: web-cache (Error)
+SecretsFound   (Ok)
+PersistentVolumeClaimsFound NotFound PersistentVolumeClaim not found in namespace default: web-data (Error)
+ServiceAccountFound NotFound ServiceAccount not found in namespace default: web (Error)
+ImagesPullable PullFailing Image registry.example.com/broken:1.0 fails to pull in pod other/worker-5d8f7c9b6-x2k4p (ImagePullBackOff) (Warning)`,
+		os.Conditions)
+
+	os = p.Predict(t.Context(), manifests[1])
+	assert.Equal(t, status.Ok, os.Status().Result)
+	assert.Empty(t, os.Conditions)
+
+	// Selecting pods from the deployment in the manifests.
+	os = p.Predict(t.Context(), manifests[2])
+	assert.Equal(t, status.Ok, os.Status().Result)
+	test.AssertConditions(t, "SelectorMatchesPods   (Ok)", os.Conditions)
+
+	// Selecting existing pods.
+	os = p.Predict(t.Context(), manifests[3])
+	assert.Equal(t, status.Ok, os.Status().Result)
+	test.AssertConditions(t, "SelectorMatchesPods   (Ok)", os.Conditions)
+
+	os = p.Predict(t.Context(), manifests[4])
+	assert.Equal(t, status.Warning, os.Status().Result)
+	test.AssertConditions(t, "SelectorMatchesPods NoPods No pods match the selector app=cache (Warning)", os.Conditions)
+}
+
+func TestPredictLoadErrors(t *testing.T) {
+	e, l, _ := test.TestEvaluator("live.yaml")
+	manifests := loadManifests(t)
+	p := predict.NewPredictor(e, manifests)
+
+	forbidden := errors.New("forbidden")
+	l.RegisterLoadError("default", schema.GroupKind{Kind: "ConfigMap"}, forbidden)
+	l.RegisterLoadError(eval.NamespaceAll, schema.GroupKind{Kind: "Pod"}, forbidden)
+
+	// The config maps can't be checked and the images are only checked in
+	// the namespace of the object.
+	os := p.Predict(t.Context(), manifests[0])
+	test.AssertConditions(t, `ConfigMapsFound LoadFailed forbidden (Unknown)
+SecretsFound   (Ok)
+PersistentVolumeClaimsFound NotFound PersistentVolumeClaim not found in namespace default: web-data (Error)
+ServiceAccountFound NotFound ServiceAccount not found in namespace default: web (Error)
+ImagesPullable  Only pods in namespace default were checked (Ok)`,
+		os.Conditions)
+
+	// Selecting existing pods that can't be loaded.
+	e, l, _ = test.TestEvaluator("live.yaml")
+	l.RegisterLoadError("default", schema.GroupKind{Kind: "Pod"}, forbidden)
+	p = predict.NewPredictor(e, manifests)
+	os = p.Predict(t.Context(), manifests[3])
+	assert.Equal(t, status.Unknown, os.Status().Result)
+	test.AssertConditions(t, "SelectorMatchesPods LoadFailed forbidden (Unknown)", os.Conditions)
+}
@@ -15,10 +15,12 @@ import (
 var (
 	gkClusterOperator                 = schema.GroupKind{Group: "config.openshift.io", Kind: "ClusterOperator"}
 	clusteroperatorConditionsAnalyzer = analyze.GenericConditionAnalyzer{
+		Name:                       "ClusterOperatorConditionsAnalyzer",
 		Conditions:                 analyze.NewStringMatchers("Available"),
 		ReversedPolarityConditions: analyze.NewStringMatchers("Degraded"),
 	}
 	insightsConditionsAnalyzer = analyze.GenericConditionAnalyzer{
+		Name:                       "InsightsConditionsAnalyzer",
 		ReversedPolarityConditions: analyze.NewStringMatchers("ClusterTransferAvailable"),
 		WarningConditions:          analyze.NewRegexpMatchers("RemoteConfiguration"),
 		ProgressingConditions:      analyze.NewStringMatchers("ClusterTransferAvailable"),
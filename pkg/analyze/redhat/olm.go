@@ -25,6 +25,7 @@ var (
 	gkOLMOperatorGroup             = schema.GroupKind{Group: "operators.coreos.com", Kind: "OperatorGroup"}
 	gkOLMCSV                       = schema.GroupKind{Group: "operators.coreos.com", Kind: "ClusterServiceVersion"}
 	subscriptionConditionsAnalyzer = analyze.GenericConditionAnalyzer{
+		Name:                       "SubscriptionConditionsAnalyzer",
 		ReversedPolarityConditions: analyze.NewStringMatchers("CatalogSourcesUnhealthy", "ResolutionFailed"),
 	}
 
@@ -131,6 +132,7 @@ func (_ OLMInstallPlanAnalyzer) Supports(obj *status.Object) bool {
 func (_ OLMInstallPlanAnalyzer) Analyze(ctx context.Context, obj *status.Object) status.ObjectStatus {
 	conditions, err := analyze.AnalyzeObjectConditions(obj, []analyze.ConditionAnalyzer{
 		analyze.GenericConditionAnalyzer{
+			Name:       "InstallPlanConditionsAnalyzer",
 			Conditions: analyze.NewStringMatchers("Installed"),
 		}})
 
@@ -40,6 +40,7 @@ func (_ RouteAnalyzer) Analyze(ctx context.Context, obj *status.Object) status.O
 		if found {
 			c, err := analyze.AnalyzeRawConditions(data,
 				[]analyze.ConditionAnalyzer{analyze.GenericConditionAnalyzer{
+					Name:       "RouteConditionsAnalyzer",
 					Conditions: analyze.NewStringMatchers("Admitted"),
 				}})
 			if err != nil {
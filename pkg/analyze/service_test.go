@@ -19,6 +19,7 @@ func TestServiceAnalyzer(t *testing.T) {
 	os = e.Eval(t.Context(), objs[0])
 	assert.False(t, os.Status().Progressing)
 	assert.Equal(t, os.Status().Result, status.Ok)
+	assert.Equal(t, status.RelationSelector, os.SubStatuses[0].Relation)
 
 	sb := &strings.Builder{}
 	p.PrintStatuses([]status.ObjectStatus{os}, sb)
@@ -0,0 +1,122 @@
+package analyze
+
+import (
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+var (
+	statusProgressing = status.Status{Result: status.Unknown, Progressing: true}
+
+	// StatusFieldValues maps well-known values of status.phase, status.state
+	// and status.health.status fields to results. The keys are lower-case:
+	// the values are matched case-insensitively. Unlisted values are reported
+	// as Unknown.
+	StatusFieldValues = map[string]status.Status{
+		"active":      {Result: status.Ok},
+		"available":   {Result: status.Ok},
+		"bound":       {Result: status.Ok},
+		"complete":    {Result: status.Ok},
+		"completed":   {Result: status.Ok},
+		"deployed":    {Result: status.Ok},
+		"established": {Result: status.Ok},
+		"healthy":     {Result: status.Ok},
+		"online":      {Result: status.Ok},
+		"ready":       {Result: status.Ok},
+		"running":     {Result: status.Ok},
+		"succeeded":   {Result: status.Ok},
+		"synced":      {Result: status.Ok},
+
+		"creating":     statusProgressing,
+		"deleting":     statusProgressing,
+		"initializing": statusProgressing,
+		"installing":   statusProgressing,
+		"pending":      statusProgressing,
+		"progressing":  statusProgressing,
+		"provisioning": statusProgressing,
+		"reconciling":  statusProgressing,
+		"starting":     statusProgressing,
+		"terminating":  statusProgressing,
+		"updating":     statusProgressing,
+		"upgrading":    statusProgressing,
+
+		"degraded":  {Result: status.Warning},
+		"missing":   {Result: status.Warning},
+		"suspended": {Result: status.Warning},
+		"warning":   {Result: status.Warning},
+
+		"error":     {Result: status.Error},
+		"failed":    {Result: status.Error},
+		"failure":   {Result: status.Error},
+		"unhealthy": {Result: status.Error},
+	}
+
+	// statusFields are the paths of the status fields recognized by
+	// AnalyzeStatusFields, with the types of the synthetic conditions.
+	statusFields = []struct {
+		condType string
+		path     []string
+	}{
+		{"Phase", []string{"status", "phase"}},
+		{"State", []string{"status", "state"}},
+		{"Health", []string{"status", "health", "status"}},
+	}
+)
+
+// AnalyzeStatusFields recognizes common shapes of the status used by resources
+// not following the conditions convention: status.phase, status.state,
+// status.health.status (e.g. Argo CD) and status.ready. The values are turned
+// into synthetic conditions, using StatusFieldValues for the mapping.
+//
+// The conditions already present in the object take precedence: the synthetic
+// condition is skipped if there is a condition with the same type.
+func AnalyzeStatusFields(obj *status.Object, conditions []status.ConditionStatus) []status.ConditionStatus {
+	var ret []status.ConditionStatus
+	hasCondition := func(condType string) bool {
+		for _, c := range conditions {
+			if strings.EqualFold(c.Type, condType) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, f := range statusFields {
+		value, found, err := unstructured.NestedString(obj.Unstructured.Object, f.path...)
+		if err != nil || !found || value == "" || hasCondition(f.condType) {
+			continue
+		}
+		ret = append(ret, statusFieldCondition(f.condType, value))
+	}
+
+	ready, found, err := unstructured.NestedBool(obj.Unstructured.Object, "status", "ready")
+	if err == nil && found && !hasCondition("Ready") {
+		cond := SyntheticCondition("Ready", ready, "", "", time.Time{})
+		if ready {
+			ret = append(ret, ConditionStatusOk(cond))
+		} else {
+			ret = append(ret, ConditionStatusError(cond))
+		}
+	}
+
+	return ret
+}
+
+func statusFieldCondition(condType, value string) status.ConditionStatus {
+	s, found := StatusFieldValues[strings.ToLower(value)]
+	cond := SyntheticCondition(condType, found && s.Result == status.Ok, value, "", time.Time{})
+	if !found {
+		return ConditionStatusUnknown(cond)
+	}
+	return status.ConditionStatus{
+		Condition: cond,
+		CondStatus: &status.Status{
+			Result:      s.Result,
+			Progressing: s.Progressing,
+		},
+	}
+}
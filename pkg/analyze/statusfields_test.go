@@ -0,0 +1,42 @@
+package analyze_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inecas/kube-health/internal/test"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+func TestStatusFields(t *testing.T) {
+	var os status.ObjectStatus
+	e, _, objs := test.TestEvaluator("statusfields.yaml")
+
+	os = e.Eval(t.Context(), objs[0])
+	assert.Equal(t, status.Ok, os.Status().Result)
+	test.AssertConditions(t, `Phase Running  (Ok)`, os.Conditions)
+
+	os = e.Eval(t.Context(), objs[1])
+	assert.Equal(t, status.Error, os.Status().Result)
+	test.AssertConditions(t, `Phase Failed  (Error)`, os.Conditions)
+
+	os = e.Eval(t.Context(), objs[2])
+	assert.True(t, os.Status().Progressing)
+	test.AssertConditions(t, `State provisioning  (Unknown)`, os.Conditions)
+
+	os = e.Eval(t.Context(), objs[3])
+	assert.Equal(t, status.Warning, os.Status().Result)
+	test.AssertConditions(t, `Health Degraded  (Warning)`, os.Conditions)
+
+	os = e.Eval(t.Context(), objs[4])
+	assert.Equal(t, status.Error, os.Status().Result)
+	test.AssertConditions(t, `Ready   (Error)`, os.Conditions)
+
+	// Existing Ready condition takes precedence over status.ready,
+	// unknown phase values are reported as Unknown.
+	os = e.Eval(t.Context(), objs[5])
+	assert.Equal(t, status.Ok, os.Status().Result)
+	test.AssertConditions(t, `Ready   (Ok)
+Phase Exotic  (Unknown)`, os.Conditions)
+}
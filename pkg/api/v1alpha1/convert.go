@@ -0,0 +1,279 @@
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+// NewHealthReport converts the evaluated statuses into the versioned report.
+func NewHealthReport(statuses []status.ObjectStatus, evaluationTime time.Time, cluster *ClusterInfo) *HealthReport {
+	report := &HealthReport{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: SchemeGroupVersion.String(),
+			Kind:       HealthReportKind,
+		},
+		EvaluationTime: metav1.NewTime(evaluationTime),
+		Cluster:        cluster,
+		Summary:        Summarize(statuses),
+		Objects:        make([]ObjectReport, 0, len(statuses)),
+	}
+
+	for _, s := range statuses {
+		report.Objects = append(report.Objects, NewObjectReport(s))
+		report.RootCauses = append(report.RootCauses, findRootCauses(s, nil)...)
+	}
+
+	return report
+}
+
+// Summarize counts the top-level statuses by their result.
+// Progressing objects are counted both in the Progressing and their result bucket.
+func Summarize(statuses []status.ObjectStatus) Summary {
+	var s Summary
+	for _, os := range statuses {
+		st := os.Status()
+		switch st.Result {
+		case status.Ok:
+			s.Ok++
+		case status.Warning:
+			s.Warning++
+		case status.Error:
+			s.Error++
+		default:
+			s.Unknown++
+		}
+		if st.Progressing {
+			s.Progressing++
+		}
+		s.Objects += countObjects(os)
+	}
+	return s
+}
+
+func countObjects(os status.ObjectStatus) int {
+	count := 1
+	for _, sub := range os.SubStatuses {
+		count += countObjects(sub)
+	}
+	return count
+}
+
+// NewObjectReport converts the object status, including the sub-objects.
+func NewObjectReport(os status.ObjectStatus) ObjectReport {
+	ret := ObjectReport{
+		Object: NewObjectReference(os.Object),
+		Health: NewHealth(os.ObjStatus),
+	}
+
+	for _, c := range os.Conditions {
+		ret.Conditions = append(ret.Conditions, NewCondition(c))
+	}
+
+	for _, sub := range os.SubStatuses {
+		ret.SubObjects = append(ret.SubObjects, NewObjectReport(sub))
+	}
+
+	for _, o := range os.Overrides {
+		ret.Overrides = append(ret.Overrides, Override{Annotation: o.Annotation, Value: o.Value})
+	}
+
+	return ret
+}
+
+func NewObjectReference(obj *status.Object) ObjectReference {
+	if obj == nil {
+		return ObjectReference{}
+	}
+	return ObjectReference{
+		APIVersion: obj.APIVersion,
+		Kind:       obj.Kind,
+		Namespace:  obj.Namespace,
+		Name:       obj.Name,
+		UID:        string(obj.UID),
+	}
+}
+
+func NewHealth(s status.Status) Health {
+	h := Health{
+		Result:      s.Result,
+		Progressing: s.Progressing,
+		Status:      s.Status,
+		Score:       s.Score,
+	}
+	if s.Err != nil {
+		h.Error = s.Err.Error()
+	}
+	if s.Provenance != nil {
+		h.Provenance = &Provenance{
+			Analyzer:         s.Provenance.Analyzer,
+			Rule:             s.Provenance.Rule,
+			ReversedPolarity: s.Provenance.ReversedPolarity,
+			Reason:           s.Provenance.Reason,
+		}
+	}
+	return h
+}
+
+func NewCondition(cs status.ConditionStatus) Condition {
+	c := Condition{}
+	if cs.Condition != nil {
+		c.Type = cs.Type
+		c.Status = string(cs.Condition.Status)
+		c.Reason = cs.Reason
+		c.Message = cs.Message
+		if !cs.LastTransitionTime.IsZero() {
+			t := cs.LastTransitionTime
+			c.LastTransitionTime = &t
+		}
+	}
+	if cs.CondStatus != nil {
+		c.Health = NewHealth(*cs.CondStatus)
+	}
+	return c
+}
+
+// findRootCauses walks the tree and returns the deepest objects with
+// Warning or Error result.
+func findRootCauses(os status.ObjectStatus, path []ObjectReference) []RootCause {
+	if os.Status().Result < status.Warning {
+		return nil
+	}
+
+	ref := NewObjectReference(os.Object)
+	subPath := append(path[:len(path):len(path)], ref)
+
+	var ret []RootCause
+	for _, sub := range os.SubStatuses {
+		ret = append(ret, findRootCauses(sub, subPath)...)
+	}
+	if len(ret) > 0 {
+		return ret
+	}
+
+	rc := RootCause{
+		Object: ref,
+		Health: NewHealth(os.ObjStatus),
+	}
+	if len(path) > 0 {
+		rc.Path = path
+	}
+	for _, c := range os.Conditions {
+		if c.Status().Result >= status.Warning {
+			rc.Conditions = append(rc.Conditions, NewCondition(c))
+		}
+	}
+	return []RootCause{rc}
+}
+
+// ReadHealthReport reads the report in JSON or YAML format, as produced
+// by the structured output.
+func ReadHealthReport(r io.Reader) (*HealthReport, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &HealthReport{}
+	if err := yaml.Unmarshal(data, report); err != nil {
+		return nil, fmt.Errorf("failed to parse the report: %w", err)
+	}
+	if report.APIVersion != SchemeGroupVersion.String() || report.Kind != HealthReportKind {
+		return nil, fmt.Errorf("unexpected document %s/%s: expected %s/%s",
+			report.APIVersion, report.Kind, SchemeGroupVersion.String(), HealthReportKind)
+	}
+	return report, nil
+}
+
+// ToObjectStatuses converts the report back to the statuses, e.g. to compare
+// a saved report with a live evaluation. The statuses don't contain the raw
+// objects: only the fields from the ObjectReference are set.
+func (in *HealthReport) ToObjectStatuses() []status.ObjectStatus {
+	ret := make([]status.ObjectStatus, 0, len(in.Objects))
+	for _, o := range in.Objects {
+		ret = append(ret, o.ToObjectStatus())
+	}
+	return ret
+}
+
+func (in ObjectReport) ToObjectStatus() status.ObjectStatus {
+	ret := status.ObjectStatus{
+		Object:    in.Object.ToObject(),
+		ObjStatus: in.Health.ToStatus(),
+	}
+
+	for _, c := range in.Conditions {
+		ret.Conditions = append(ret.Conditions, c.ToConditionStatus())
+	}
+
+	for _, sub := range in.SubObjects {
+		ret.SubStatuses = append(ret.SubStatuses, sub.ToObjectStatus())
+	}
+
+	for _, o := range in.Overrides {
+		ret.Overrides = append(ret.Overrides, status.Override{Annotation: o.Annotation, Value: o.Value})
+	}
+
+	return ret
+}
+
+func (in ObjectReference) ToObject() *status.Object {
+	obj := &status.Object{
+		TypeMeta: metav1.TypeMeta{APIVersion: in.APIVersion, Kind: in.Kind},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: in.Namespace,
+			Name:      in.Name,
+			UID:       types.UID(in.UID),
+		},
+		Unstructured: &unstructured.Unstructured{},
+	}
+	obj.Unstructured.SetAPIVersion(in.APIVersion)
+	obj.Unstructured.SetKind(in.Kind)
+	obj.Unstructured.SetNamespace(in.Namespace)
+	obj.Unstructured.SetName(in.Name)
+	obj.Unstructured.SetUID(types.UID(in.UID))
+	return obj
+}
+
+func (in Health) ToStatus() status.Status {
+	s := status.Status{
+		Result:      in.Result,
+		Progressing: in.Progressing,
+		Status:      in.Status,
+		Score:       in.Score,
+	}
+	if in.Error != "" {
+		s.Err = errors.New(in.Error)
+	}
+	if in.Provenance != nil {
+		s.Provenance = &status.Provenance{
+			Analyzer:         in.Provenance.Analyzer,
+			Rule:             in.Provenance.Rule,
+			ReversedPolarity: in.Provenance.ReversedPolarity,
+			Reason:           in.Provenance.Reason,
+		}
+	}
+	return s
+}
+
+func (in Condition) ToConditionStatus() status.ConditionStatus {
+	cond := &metav1.Condition{
+		Type:    in.Type,
+		Status:  metav1.ConditionStatus(in.Status),
+		Reason:  in.Reason,
+		Message: in.Message,
+	}
+	if in.LastTransitionTime != nil {
+		cond.LastTransitionTime = *in.LastTransitionTime
+	}
+	health := in.Health.ToStatus()
+	return status.ConditionStatus{Condition: cond, CondStatus: &health}
+}
@@ -0,0 +1,10 @@
+package v1alpha1
+
+import (
+	_ "embed"
+)
+
+// HealthReportSchema is the JSON schema of the HealthReport document.
+//
+//go:embed healthreport.schema.json
+var HealthReportSchema []byte
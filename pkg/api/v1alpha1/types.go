@@ -0,0 +1,277 @@
+// Package v1alpha1 contains the versioned, public representation of the
+// kube-health evaluation results.
+//
+// The types in this package define the stable contract for tools consuming
+// the structured (json/yaml) output of kube-health. Internal types in the
+// `status` package can evolve freely: any change to the types here needs
+// to be reflected in the JSON schema (see healthreport.schema.json) and
+// is subject to the API versioning rules.
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+const (
+	GroupName = "kube-health.io"
+	Version   = "v1alpha1"
+
+	HealthReportKind = "HealthReport"
+)
+
+var (
+	// SchemeGroupVersion is the group version used for the report types.
+	SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: Version}
+)
+
+// HealthReport is the top-level document produced by kube-health
+// for the structured output.
+type HealthReport struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// EvaluationTime is the time the report was produced.
+	EvaluationTime metav1.Time `json:"evaluationTime"`
+
+	// EvaluationDuration is how long the evaluation of the objects took.
+	EvaluationDuration *metav1.Duration `json:"evaluationDuration,omitempty"`
+
+	// Sequence numbers the reports streamed during a single run, starting at 1.
+	Sequence int64 `json:"sequence,omitempty"`
+
+	// Cluster identifies the cluster the objects were evaluated against.
+	Cluster *ClusterInfo `json:"cluster,omitempty"`
+
+	// Wait describes the waiting for the objects, if requested.
+	Wait *WaitInfo `json:"wait,omitempty"`
+
+	// Error is set when the evaluation failed, e.g. when the API server
+	// was not reachable. The report contains only the objects that were
+	// evaluated successfully then.
+	Error string `json:"error,omitempty"`
+
+	// Summary contains aggregated counts of the results.
+	Summary Summary `json:"summary"`
+
+	// RootCauses lists the deepest unhealthy objects in the evaluated trees,
+	// i.e. objects with Warning or Error result whose sub-objects are all healthy.
+	RootCauses []RootCause `json:"rootCauses,omitempty"`
+
+	// Objects contains the evaluated objects, including their sub-objects.
+	Objects []ObjectReport `json:"objects"`
+}
+
+// ClusterInfo identifies the evaluated cluster.
+type ClusterInfo struct {
+	// Context is the name of the kubeconfig context used.
+	Context string `json:"context,omitempty"`
+	// Server is the address of the API server.
+	Server string `json:"server,omitempty"`
+}
+
+// WaitInfo describes the waiting for the objects.
+type WaitInfo struct {
+	// Strategy is the active wait strategy, e.g. "ok" or "condition=Available=True".
+	Strategy string `json:"strategy"`
+	// Elapsed is the time spent waiting so far.
+	Elapsed metav1.Duration `json:"elapsed"`
+}
+
+// Summary contains counts of the top-level objects by their result.
+type Summary struct {
+	// Objects is the total number of evaluated objects, including sub-objects.
+	Objects int `json:"objects"`
+
+	Ok          int `json:"ok"`
+	Warning     int `json:"warning"`
+	Error       int `json:"error"`
+	Unknown     int `json:"unknown"`
+	Progressing int `json:"progressing"`
+}
+
+// Health is the health of an object or a condition.
+type Health struct {
+	Result      status.Result `json:"result"`
+	Progressing bool          `json:"progressing"`
+	// Status is a human-readable status.
+	Status string `json:"status,omitempty"`
+	// Error is set when the evaluation itself failed.
+	Error string `json:"error,omitempty"`
+	// Score is an optional health score between 0 (unhealthy) and 100 (healthy).
+	Score *int `json:"score,omitempty"`
+	// Provenance describes how the result was determined. It's set only
+	// in the explain mode.
+	Provenance *Provenance `json:"provenance,omitempty"`
+}
+
+// Provenance describes how the result was determined.
+type Provenance struct {
+	// Analyzer that produced the result.
+	Analyzer string `json:"analyzer,omitempty"`
+	// Rule that matched, e.g. the condition matcher or the aggregation policy.
+	Rule string `json:"rule,omitempty"`
+	// ReversedPolarity is set for conditions where True means unhealthy.
+	ReversedPolarity bool `json:"reversedPolarity,omitempty"`
+	// Reason describes why the result was chosen.
+	Reason string `json:"reason,omitempty"`
+}
+
+// ObjectReference identifies the evaluated object.
+type ObjectReference struct {
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	UID        string `json:"uid,omitempty"`
+}
+
+// Condition is a condition of the object together with its health.
+type Condition struct {
+	Type               string       `json:"type"`
+	Status             string       `json:"status"`
+	Reason             string       `json:"reason,omitempty"`
+	Message            string       `json:"message,omitempty"`
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+	Health             Health       `json:"health"`
+}
+
+// ObjectReport holds the health details of a single object.
+type ObjectReport struct {
+	Object     ObjectReference `json:"object"`
+	Health     Health          `json:"health"`
+	Conditions []Condition     `json:"conditions,omitempty"`
+	SubObjects []ObjectReport  `json:"subObjects,omitempty"`
+	// Overrides lists the annotations that changed the evaluation result.
+	Overrides []Override `json:"overrides,omitempty"`
+}
+
+// Override is an annotation on the object that changed the evaluation result.
+type Override struct {
+	Annotation string `json:"annotation"`
+	Value      string `json:"value"`
+}
+
+// RootCause points to an object that is the most likely source
+// of the unhealthy status of a top-level object.
+type RootCause struct {
+	Object ObjectReference `json:"object"`
+	// Path is the chain of objects from the top-level object to the root cause
+	// (excluding the root cause itself).
+	Path   []ObjectReference `json:"path,omitempty"`
+	Health Health            `json:"health"`
+	// Conditions contains only the unhealthy conditions of the object.
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// HealthReport implements runtime.Object interface.
+var _ runtime.Object = &HealthReport{}
+
+func (in *HealthReport) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+func (in *HealthReport) DeepCopy() *HealthReport {
+	if in == nil {
+		return nil
+	}
+	out := &HealthReport{
+		TypeMeta:       in.TypeMeta,
+		EvaluationTime: *in.EvaluationTime.DeepCopy(),
+		Sequence:       in.Sequence,
+		Summary:        in.Summary,
+		Error:          in.Error,
+	}
+	if in.EvaluationDuration != nil {
+		duration := *in.EvaluationDuration
+		out.EvaluationDuration = &duration
+	}
+	if in.Cluster != nil {
+		cluster := *in.Cluster
+		out.Cluster = &cluster
+	}
+	if in.Wait != nil {
+		wait := *in.Wait
+		out.Wait = &wait
+	}
+	if in.RootCauses != nil {
+		out.RootCauses = make([]RootCause, len(in.RootCauses))
+		for i := range in.RootCauses {
+			out.RootCauses[i] = *in.RootCauses[i].DeepCopy()
+		}
+	}
+	if in.Objects != nil {
+		out.Objects = make([]ObjectReport, len(in.Objects))
+		for i := range in.Objects {
+			out.Objects[i] = *in.Objects[i].DeepCopy()
+		}
+	}
+	return out
+}
+
+func (in *ObjectReport) DeepCopy() *ObjectReport {
+	out := &ObjectReport{
+		Object:     in.Object,
+		Health:     *in.Health.DeepCopy(),
+		Conditions: copyConditions(in.Conditions),
+	}
+	if in.Overrides != nil {
+		out.Overrides = make([]Override, len(in.Overrides))
+		copy(out.Overrides, in.Overrides)
+	}
+	if in.SubObjects != nil {
+		out.SubObjects = make([]ObjectReport, len(in.SubObjects))
+		for i := range in.SubObjects {
+			out.SubObjects[i] = *in.SubObjects[i].DeepCopy()
+		}
+	}
+	return out
+}
+
+func (in *RootCause) DeepCopy() *RootCause {
+	out := &RootCause{
+		Object:     in.Object,
+		Health:     *in.Health.DeepCopy(),
+		Conditions: copyConditions(in.Conditions),
+	}
+	if in.Path != nil {
+		out.Path = make([]ObjectReference, len(in.Path))
+		copy(out.Path, in.Path)
+	}
+	return out
+}
+
+func (in *Condition) DeepCopy() *Condition {
+	out := *in
+	if in.LastTransitionTime != nil {
+		out.LastTransitionTime = in.LastTransitionTime.DeepCopy()
+	}
+	out.Health = *in.Health.DeepCopy()
+	return &out
+}
+
+func (in *Health) DeepCopy() *Health {
+	out := *in
+	if in.Score != nil {
+		score := *in.Score
+		out.Score = &score
+	}
+	if in.Provenance != nil {
+		provenance := *in.Provenance
+		out.Provenance = &provenance
+	}
+	return &out
+}
+
+func copyConditions(in []Condition) []Condition {
+	if in == nil {
+		return nil
+	}
+	out := make([]Condition, len(in))
+	for i := range in {
+		out[i] = *in[i].DeepCopy()
+	}
+	return out
+}
@@ -0,0 +1,236 @@
+package v1alpha1_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+
+	"github.com/inecas/kube-health/pkg/api/v1alpha1"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+func testObject(kind, ns, name string) *status.Object {
+	return &status.Object{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: kind},
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name, UID: types.UID("uid-" + name)},
+	}
+}
+
+func testCondition(condType string, condStatus metav1.ConditionStatus, res status.Result) status.ConditionStatus {
+	return status.ConditionStatus{
+		Condition: &metav1.Condition{
+			Type:               condType,
+			Status:             condStatus,
+			Reason:             condType + "Reason",
+			Message:            condType + " message",
+			LastTransitionTime: metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)),
+		},
+		CondStatus: &status.Status{Result: res},
+	}
+}
+
+func testStatuses() []status.ObjectStatus {
+	container := status.ObjectStatus{
+		Object:    &status.Object{TypeMeta: metav1.TypeMeta{Kind: "Container"}, ObjectMeta: metav1.ObjectMeta{Name: "c1"}},
+		ObjStatus: status.Status{Result: status.Error, Status: "Error"},
+		Conditions: []status.ConditionStatus{
+			testCondition("Waiting", metav1.ConditionTrue, status.Error),
+		},
+	}
+	container.Conditions[0].CondStatus.Provenance = &status.Provenance{
+		Analyzer: "CommonConditionsAnalyzer", Rule: "ReversedPolarityConditions Waiting", ReversedPolarity: true,
+	}
+	score := 0
+	pod := status.ObjectStatus{
+		Object:    testObject("Pod", "default", "p1"),
+		ObjStatus: status.Status{Result: status.Error, Status: "Error", Score: &score},
+		Conditions: []status.ConditionStatus{
+			testCondition("Ready", metav1.ConditionFalse, status.Error),
+			testCondition("PodScheduled", metav1.ConditionTrue, status.Ok),
+		},
+		SubStatuses: []status.ObjectStatus{container},
+	}
+	rs := status.ObjectStatus{
+		Object: testObject("ReplicaSet", "default", "rs1"),
+		ObjStatus: status.Status{Result: status.Error, Progressing: true, Status: "Error",
+			Provenance: &status.Provenance{Analyzer: "analyze.ReplicaSetAnalyzer", Rule: "aggregation worst-of",
+				Reason: "sub-objects aggregated to Error (Pod/p1 is Error)"}},
+		SubStatuses: []status.ObjectStatus{pod},
+	}
+	pvc := status.ObjectStatus{
+		Object:    testObject("PersistentVolumeClaim", "default", "pvc1"),
+		ObjStatus: status.Status{Result: status.Unknown, Status: "Unknown", Err: errors.New("not found")},
+		Overrides: []status.Override{{Annotation: status.AnnotationExpectedResult, Value: "warning"}},
+	}
+	return []status.ObjectStatus{rs, pvc}
+}
+
+func TestNewHealthReport(t *testing.T) {
+	evalTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)
+	report := v1alpha1.NewHealthReport(testStatuses(), evalTime,
+		&v1alpha1.ClusterInfo{Context: "test", Server: "https://api.example.com:6443"})
+
+	assert.Equal(t, "kube-health.io/v1alpha1", report.APIVersion)
+	assert.Equal(t, "HealthReport", report.Kind)
+	assert.Equal(t, v1alpha1.Summary{Objects: 4, Error: 1, Unknown: 1, Progressing: 1}, report.Summary)
+
+	require.Len(t, report.RootCauses, 1)
+	rc := report.RootCauses[0]
+	assert.Equal(t, "c1", rc.Object.Name)
+	assert.Equal(t, []string{"rs1", "p1"}, []string{rc.Path[0].Name, rc.Path[1].Name})
+	require.Len(t, rc.Conditions, 1)
+	assert.Equal(t, "Waiting", rc.Conditions[0].Type)
+
+	assert.Equal(t, "not found", report.Objects[1].Health.Error)
+	assert.Equal(t, "Error", report.Objects[0].Health.Status)
+
+	assert.Equal(t, &v1alpha1.Provenance{Analyzer: "analyze.ReplicaSetAnalyzer", Rule: "aggregation worst-of",
+		Reason: "sub-objects aggregated to Error (Pod/p1 is Error)"}, report.Objects[0].Health.Provenance)
+	assert.Equal(t, &v1alpha1.Provenance{Analyzer: "CommonConditionsAnalyzer",
+		Rule: "ReversedPolarityConditions Waiting", ReversedPolarity: true}, rc.Conditions[0].Health.Provenance)
+	assert.Nil(t, report.Objects[1].Health.Provenance)
+}
+
+func TestHealthReportRoundTrip(t *testing.T) {
+	report := v1alpha1.NewHealthReport(testStatuses(), time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local),
+		&v1alpha1.ClusterInfo{Context: "test"})
+	report.EvaluationDuration = &metav1.Duration{Duration: 1500 * time.Millisecond}
+	report.Sequence = 3
+	report.Error = "connection refused"
+	report.Wait = &v1alpha1.WaitInfo{Strategy: "ok", Elapsed: metav1.Duration{Duration: time.Minute}}
+
+	data, err := json.Marshal(report)
+	require.NoError(t, err)
+
+	var decoded v1alpha1.HealthReport
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, report, &decoded)
+
+	yamlData, err := yaml.Marshal(report)
+	require.NoError(t, err)
+
+	var yamlDecoded v1alpha1.HealthReport
+	require.NoError(t, yaml.Unmarshal(yamlData, &yamlDecoded))
+	assert.Equal(t, report, &yamlDecoded)
+
+	assert.Equal(t, report, report.DeepCopyObject())
+}
+
+func TestReadHealthReport(t *testing.T) {
+	report := v1alpha1.NewHealthReport(testStatuses(), time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local),
+		&v1alpha1.ClusterInfo{Context: "test"})
+
+	yamlData, err := yaml.Marshal(report)
+	require.NoError(t, err)
+
+	read, err := v1alpha1.ReadHealthReport(bytes.NewReader(yamlData))
+	require.NoError(t, err)
+	assert.Equal(t, report, read)
+
+	// Converting the statuses back leads to the same report.
+	converted := v1alpha1.NewHealthReport(read.ToObjectStatuses(), report.EvaluationTime.Time, report.Cluster)
+	assert.Equal(t, report, converted)
+
+	_, err = v1alpha1.ReadHealthReport(strings.NewReader("apiVersion: v1\nkind: List\n"))
+	assert.ErrorContains(t, err, "unexpected document v1/List")
+}
+
+func TestHealthReportSchema(t *testing.T) {
+	var schema map[string]interface{}
+	require.NoError(t, json.Unmarshal(v1alpha1.HealthReportSchema, &schema))
+
+	report := v1alpha1.NewHealthReport(testStatuses(), time.Now(), &v1alpha1.ClusterInfo{Context: "test"})
+	report.EvaluationDuration = &metav1.Duration{Duration: time.Second}
+	report.Sequence = 1
+	report.Wait = &v1alpha1.WaitInfo{Strategy: "ok", Elapsed: metav1.Duration{Duration: time.Minute}}
+	report.Error = "the server is currently unable to handle the request"
+	data, err := json.Marshal(report)
+	require.NoError(t, err)
+
+	var doc interface{}
+	require.NoError(t, json.Unmarshal(data, &doc))
+
+	errs := validate(schema, schema, doc, "")
+	assert.Empty(t, errs)
+}
+
+// validate is a minimal JSON schema validator, supporting the subset of the
+// schema features used by the HealthReport schema.
+func validate(root, schema map[string]interface{}, doc interface{}, path string) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/$defs/")
+		def := root["$defs"].(map[string]interface{})[name].(map[string]interface{})
+		return validate(root, def, doc, path)
+	}
+
+	var errs []string
+	if c, ok := schema["const"]; ok && c != doc {
+		errs = append(errs, fmt.Sprintf("%s: expected %v, got %v", path, c, doc))
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, e := range enum {
+			if e == doc {
+				found = true
+			}
+		}
+		if !found {
+			errs = append(errs, fmt.Sprintf("%s: %v not in %v", path, doc, enum))
+		}
+	}
+
+	switch schema["type"] {
+	case "object":
+		obj, ok := doc.(map[string]interface{})
+		if !ok {
+			return append(errs, fmt.Sprintf("%s: expected object", path))
+		}
+		props, _ := schema["properties"].(map[string]interface{})
+		required, _ := schema["required"].([]interface{})
+		for _, req := range required {
+			if _, found := obj[req.(string)]; !found {
+				errs = append(errs, fmt.Sprintf("%s: missing required %s", path, req))
+			}
+		}
+		for k, v := range obj {
+			propSchema, found := props[k]
+			if !found {
+				errs = append(errs, fmt.Sprintf("%s: unexpected property %s", path, k))
+				continue
+			}
+			errs = append(errs, validate(root, propSchema.(map[string]interface{}), v, path+"."+k)...)
+		}
+	case "array":
+		arr, ok := doc.([]interface{})
+		if !ok {
+			return append(errs, fmt.Sprintf("%s: expected array", path))
+		}
+		for i, item := range arr {
+			errs = append(errs, validate(root, schema["items"].(map[string]interface{}), item,
+				fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case "string":
+		if _, ok := doc.(string); !ok {
+			errs = append(errs, fmt.Sprintf("%s: expected string", path))
+		}
+	case "integer":
+		if _, ok := doc.(float64); !ok {
+			errs = append(errs, fmt.Sprintf("%s: expected integer", path))
+		}
+	case "boolean":
+		if _, ok := doc.(bool); !ok {
+			errs = append(errs, fmt.Sprintf("%s: expected boolean", path))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,281 @@
+// Package diff compares two sets of evaluated statuses, e.g. before and after
+// applying a manifest or the same objects in two clusters.
+//
+// The top-level objects are aligned by their GroupKind, namespace and name.
+// The sub-objects are only compared with the sub-objects of the matching
+// parent. Their names are often generated (e.g. pods of a ReplicaSet) and
+// differ between clusters or after a rollout, so the sub-objects without
+// a counterpart of the same name are aligned by their GroupKind and result
+// instead: only the number of the sub-objects per kind and their results
+// are compared then.
+package diff
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+// Change describes how an object or a condition differs between the statuses.
+type Change int
+
+const (
+	Unchanged Change = iota
+	Added
+	Removed
+	Changed
+)
+
+func (c Change) String() string {
+	switch c {
+	case Added:
+		return "Added"
+	case Removed:
+		return "Removed"
+	case Changed:
+		return "Changed"
+	default:
+		return "Unchanged"
+	}
+}
+
+// ObjectDiff is a difference of an object status. Old is nil for added objects,
+// New is nil for removed ones.
+type ObjectDiff struct {
+	Change     Change
+	Old        *status.ObjectStatus
+	New        *status.ObjectStatus
+	Conditions []ConditionDiff
+	SubObjects []ObjectDiff
+}
+
+// Object returns the most recent version of the object.
+func (d ObjectDiff) Object() *status.Object {
+	if d.New != nil {
+		return d.New.Object
+	}
+	return d.Old.Object
+}
+
+// HasChanges returns true if the object or any of its sub-objects changed.
+func (d ObjectDiff) HasChanges() bool {
+	if d.Change != Unchanged {
+		return true
+	}
+	for _, sub := range d.SubObjects {
+		if sub.HasChanges() {
+			return true
+		}
+	}
+	return false
+}
+
+// ConditionDiff is a difference of a condition. Old is nil for added conditions,
+// New is nil for removed ones.
+type ConditionDiff struct {
+	Change Change
+	Type   string
+	Old    *status.ConditionStatus
+	New    *status.ConditionStatus
+}
+
+// Diff compares the old and new statuses, including the sub-objects and conditions.
+func Diff(oldStatuses, newStatuses []status.ObjectStatus) []ObjectDiff {
+	var ret []ObjectDiff
+	newByKey := make(map[string]*status.ObjectStatus, len(newStatuses))
+	for i := range newStatuses {
+		newByKey[Key(newStatuses[i].Object)] = &newStatuses[i]
+	}
+
+	seen := make(map[string]bool, len(oldStatuses))
+	for i := range oldStatuses {
+		oldStatus := &oldStatuses[i]
+		key := Key(oldStatus.Object)
+		seen[key] = true
+		if newStatus, found := newByKey[key]; found {
+			ret = append(ret, diffObject(oldStatus, newStatus))
+		} else {
+			ret = append(ret, onlyOne(Removed, oldStatus))
+		}
+	}
+
+	for i := range newStatuses {
+		if !seen[Key(newStatuses[i].Object)] {
+			ret = append(ret, onlyOne(Added, &newStatuses[i]))
+		}
+	}
+
+	slices.SortFunc(ret, func(a, b ObjectDiff) int {
+		return strings.Compare(Key(a.Object()), Key(b.Object()))
+	})
+	return ret
+}
+
+// Key identifies the object for the alignment.
+func Key(obj *status.Object) string {
+	if obj == nil {
+		return ""
+	}
+	gk := obj.GroupVersionKind().GroupKind()
+	return fmt.Sprintf("%s/%s/%s", gk, obj.GetNamespace(), obj.GetName())
+}
+
+func diffObject(oldStatus, newStatus *status.ObjectStatus) ObjectDiff {
+	d := ObjectDiff{
+		Old:        oldStatus,
+		New:        newStatus,
+		Conditions: diffConditions(oldStatus.Conditions, newStatus.Conditions),
+		SubObjects: diffSubObjects(oldStatus.SubStatuses, newStatus.SubStatuses),
+	}
+
+	oldS, newS := oldStatus.Status(), newStatus.Status()
+	if oldS.Result != newS.Result || oldS.Progressing != newS.Progressing {
+		d.Change = Changed
+	}
+	for _, c := range d.Conditions {
+		if c.Change != Unchanged {
+			d.Change = Changed
+		}
+	}
+	return d
+}
+
+// diffSubObjects compares the sub-objects of matching parents. The sub-objects
+// are aligned by the Key first, the remaining ones by their kind, the most
+// severe results first.
+func diffSubObjects(oldStatuses, newStatuses []status.ObjectStatus) []ObjectDiff {
+	var ret []ObjectDiff
+	newByKey := make(map[string]int, len(newStatuses))
+	for i := range newStatuses {
+		newByKey[Key(newStatuses[i].Object)] = i
+	}
+
+	matchedNew := make(map[int]bool)
+	var oldRest []*status.ObjectStatus
+	for i := range oldStatuses {
+		if j, found := newByKey[Key(oldStatuses[i].Object)]; found && !matchedNew[j] {
+			matchedNew[j] = true
+			ret = append(ret, diffObject(&oldStatuses[i], &newStatuses[j]))
+		} else {
+			oldRest = append(oldRest, &oldStatuses[i])
+		}
+	}
+	var newRest []*status.ObjectStatus
+	for j := range newStatuses {
+		if !matchedNew[j] {
+			newRest = append(newRest, &newStatuses[j])
+		}
+	}
+
+	oldByKind, newByKind := groupByKind(oldRest), groupByKind(newRest)
+	for _, os := range oldRest {
+		kind := kindKey(os.Object)
+		if olds, found := oldByKind[kind]; found {
+			delete(oldByKind, kind)
+			news := newByKind[kind]
+			delete(newByKind, kind)
+			for i := 0; i < max(len(olds), len(news)); i++ {
+				switch {
+				case i >= len(news):
+					ret = append(ret, onlyOne(Removed, olds[i]))
+				case i >= len(olds):
+					ret = append(ret, onlyOne(Added, news[i]))
+				default:
+					ret = append(ret, diffObject(olds[i], news[i]))
+				}
+			}
+		}
+	}
+	for _, os := range newRest {
+		if news, found := newByKind[kindKey(os.Object)]; found {
+			delete(newByKind, kindKey(os.Object))
+			for _, n := range news {
+				ret = append(ret, onlyOne(Added, n))
+			}
+		}
+	}
+
+	slices.SortStableFunc(ret, func(a, b ObjectDiff) int {
+		return strings.Compare(Key(a.Object()), Key(b.Object()))
+	})
+	return ret
+}
+
+// groupByKind groups the objects by kindKey, sorted by severity and name.
+func groupByKind(statuses []*status.ObjectStatus) map[string][]*status.ObjectStatus {
+	ret := make(map[string][]*status.ObjectStatus)
+	for _, os := range statuses {
+		ret[kindKey(os.Object)] = append(ret[kindKey(os.Object)], os)
+	}
+	for _, group := range ret {
+		slices.SortStableFunc(group, func(a, b *status.ObjectStatus) int {
+			if a.Status().Result != b.Status().Result {
+				return int(b.Status().Result) - int(a.Status().Result)
+			}
+			return strings.Compare(a.Object.GetName(), b.Object.GetName())
+		})
+	}
+	return ret
+}
+
+func kindKey(obj *status.Object) string {
+	if obj == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s", obj.GroupVersionKind().GroupKind(), obj.GetNamespace())
+}
+
+// onlyOne creates the diff for an object present only in one of the statuses,
+// including its sub-objects and conditions.
+func onlyOne(change Change, os *status.ObjectStatus) ObjectDiff {
+	d := ObjectDiff{Change: change}
+	if change == Added {
+		d.New = os
+		d.Conditions = diffConditions(nil, os.Conditions)
+		d.SubObjects = diffSubObjects(nil, os.SubStatuses)
+	} else {
+		d.Old = os
+		d.Conditions = diffConditions(os.Conditions, nil)
+		d.SubObjects = diffSubObjects(os.SubStatuses, nil)
+	}
+	return d
+}
+
+// diffConditions compares the conditions by type. The condition is considered
+// changed when its status, reason or result differ: the messages and times
+// are ignored, as they often change without any change in the health.
+func diffConditions(oldConds, newConds []status.ConditionStatus) []ConditionDiff {
+	var ret []ConditionDiff
+	for i := range oldConds {
+		oldCond := &oldConds[i]
+		j := slices.IndexFunc(newConds, func(c status.ConditionStatus) bool {
+			return c.Type == oldCond.Type
+		})
+		if j < 0 {
+			ret = append(ret, ConditionDiff{Change: Removed, Type: oldCond.Type, Old: oldCond})
+			continue
+		}
+
+		newCond := &newConds[j]
+		change := Unchanged
+		if oldCond.Condition.Status != newCond.Condition.Status ||
+			oldCond.Reason != newCond.Reason ||
+			oldCond.Status().Result != newCond.Status().Result ||
+			oldCond.Status().Progressing != newCond.Status().Progressing {
+			change = Changed
+		}
+		ret = append(ret, ConditionDiff{Change: change, Type: oldCond.Type, Old: oldCond, New: newCond})
+	}
+
+	for i := range newConds {
+		newCond := &newConds[i]
+		if !slices.ContainsFunc(oldConds, func(c status.ConditionStatus) bool {
+			return c.Type == newCond.Type
+		}) {
+			ret = append(ret, ConditionDiff{Change: Added, Type: newCond.Type, New: newCond})
+		}
+	}
+	return ret
+}
@@ -0,0 +1,133 @@
+package diff_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/inecas/kube-health/pkg/diff"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+func testStatus(kind, name string, res status.Result, conds ...status.ConditionStatus) status.ObjectStatus {
+	return status.ObjectStatus{
+		Object: &status.Object{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: kind},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		},
+		ObjStatus:  status.Status{Result: res, Status: res.String()},
+		Conditions: conds,
+	}
+}
+
+func testCondition(condType string, condStatus metav1.ConditionStatus, reason string, res status.Result) status.ConditionStatus {
+	return status.ConditionStatus{
+		Condition:  &metav1.Condition{Type: condType, Status: condStatus, Reason: reason, Message: reason + " message"},
+		CondStatus: &status.Status{Result: res},
+	}
+}
+
+func TestDiff(t *testing.T) {
+	oldWeb := testStatus("Deployment", "web", status.Ok,
+		testCondition("Available", metav1.ConditionTrue, "MinimumReplicasAvailable", status.Ok),
+		testCondition("Progressing", metav1.ConditionFalse, "NewReplicaSetAvailable", status.Ok))
+	oldWeb.SubStatuses = []status.ObjectStatus{
+		testStatus("ReplicaSet", "web-1", status.Ok),
+		testStatus("ReplicaSet", "web-2", status.Ok),
+	}
+	oldStatuses := []status.ObjectStatus{
+		oldWeb,
+		testStatus("Deployment", "db", status.Ok),
+		testStatus("StatefulSet", "cache", status.Ok),
+	}
+
+	newWeb := testStatus("Deployment", "web", status.Error,
+		testCondition("Available", metav1.ConditionFalse, "MinimumReplicasUnavailable", status.Error),
+		testCondition("ReplicaFailure", metav1.ConditionTrue, "FailedCreate", status.Error))
+	newWeb.SubStatuses = []status.ObjectStatus{
+		testStatus("ReplicaSet", "web-2", status.Ok),
+		testStatus("ReplicaSet", "web-3", status.Error),
+	}
+	newDb := testStatus("Deployment", "db", status.Ok)
+	newStatuses := []status.ObjectStatus{
+		newWeb,
+		newDb,
+		testStatus("Deployment", "api", status.Warning),
+	}
+
+	diffs := diff.Diff(oldStatuses, newStatuses)
+	require.Len(t, diffs, 4)
+
+	assert.Equal(t, "Deployment.apps/default/api", diff.Key(diffs[0].Object()))
+	assert.Equal(t, diff.Added, diffs[0].Change)
+	assert.Nil(t, diffs[0].Old)
+
+	assert.Equal(t, "Deployment.apps/default/db", diff.Key(diffs[1].Object()))
+	assert.Equal(t, diff.Unchanged, diffs[1].Change)
+	assert.False(t, diffs[1].HasChanges())
+
+	web := diffs[2]
+	assert.Equal(t, "Deployment.apps/default/web", diff.Key(web.Object()))
+	assert.Equal(t, diff.Changed, web.Change)
+	require.Len(t, web.Conditions, 3)
+	assert.Equal(t, []diff.Change{diff.Changed, diff.Removed, diff.Added},
+		[]diff.Change{web.Conditions[0].Change, web.Conditions[1].Change, web.Conditions[2].Change})
+	assert.Equal(t, "ReplicaFailure", web.Conditions[2].Type)
+
+	// The ReplicaSets without the counterpart of the same name are aligned by kind.
+	require.Len(t, web.SubObjects, 2)
+	assert.Equal(t, diff.Unchanged, web.SubObjects[0].Change)
+	assert.Equal(t, "web-2", web.SubObjects[0].Object().GetName())
+	assert.Equal(t, diff.Changed, web.SubObjects[1].Change)
+	assert.Equal(t, "web-1", web.SubObjects[1].Old.Object.GetName())
+	assert.Equal(t, "web-3", web.SubObjects[1].New.Object.GetName())
+
+	assert.Equal(t, "StatefulSet.apps/default/cache", diff.Key(diffs[3].Object()))
+	assert.Equal(t, diff.Removed, diffs[3].Change)
+	assert.Nil(t, diffs[3].New)
+}
+
+func TestDiffIgnoresMessages(t *testing.T) {
+	oldStatus := testStatus("Deployment", "web", status.Ok,
+		testCondition("Available", metav1.ConditionTrue, "MinimumReplicasAvailable", status.Ok))
+	newStatus := testStatus("Deployment", "web", status.Ok,
+		testCondition("Available", metav1.ConditionTrue, "MinimumReplicasAvailable", status.Ok))
+	newStatus.Conditions[0].Message = "Deployment has minimum availability."
+
+	diffs := diff.Diff([]status.ObjectStatus{oldStatus}, []status.ObjectStatus{newStatus})
+	require.Len(t, diffs, 1)
+	assert.False(t, diffs[0].HasChanges())
+}
+
+func TestDiffGeneratedNames(t *testing.T) {
+	oldWeb := testStatus("Deployment", "web", status.Ok)
+	oldRs := testStatus("ReplicaSet", "web-7d9c", status.Ok)
+	oldRs.SubStatuses = []status.ObjectStatus{
+		testStatus("Pod", "web-7d9c-x2k", status.Ok),
+		testStatus("Pod", "web-7d9c-a8f", status.Ok),
+	}
+	oldWeb.SubStatuses = []status.ObjectStatus{oldRs}
+
+	newWeb := testStatus("Deployment", "web", status.Ok)
+	newRs := testStatus("ReplicaSet", "web-5f6b", status.Ok)
+	newRs.SubStatuses = []status.ObjectStatus{
+		testStatus("Pod", "web-5f6b-q1w", status.Ok),
+		testStatus("Pod", "web-5f6b-z9p", status.Ok),
+	}
+	newWeb.SubStatuses = []status.ObjectStatus{newRs}
+
+	diffs := diff.Diff([]status.ObjectStatus{oldWeb}, []status.ObjectStatus{newWeb})
+	require.Len(t, diffs, 1)
+	assert.False(t, diffs[0].HasChanges())
+
+	// A missing pod is still reported.
+	newRs.SubStatuses = newRs.SubStatuses[:1]
+	newWeb.SubStatuses = []status.ObjectStatus{newRs}
+	diffs = diff.Diff([]status.ObjectStatus{oldWeb}, []status.ObjectStatus{newWeb})
+	require.Len(t, diffs, 1)
+	assert.True(t, diffs[0].HasChanges())
+	require.Len(t, diffs[0].SubObjects[0].SubObjects, 2)
+	assert.Equal(t, diff.Removed, diffs[0].SubObjects[0].SubObjects[1].Change)
+}
@@ -2,6 +2,7 @@ package eval
 
 import (
 	"context"
+	"fmt"
 	"slices"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -20,6 +21,13 @@ type Analyzer interface {
 	Supports(obj *status.Object) bool
 }
 
+// NamedAnalyzer is an analyzer identifying itself in the explain output.
+// Analyzers not implementing it are identified by their type.
+type NamedAnalyzer interface {
+	Analyzer
+	Name() string
+}
+
 // AnalyzerInit is a function that initializes an Analyzer and can
 // optionally pass an Evaluator reference to it.
 type AnalyzerInit func(*Evaluator) Analyzer
@@ -33,8 +41,8 @@ type Loader interface {
 	// Load evaluates the query based on the backend data.
 	Load(c context.Context, ns string, gkm GroupKindMatcher, exclude []schema.GroupKind) ([]*status.Object, error)
 
-	// Load evaluates the query based on the backend data.
-	LoadPodLogs(c context.Context, obj *status.Object, container string, tailLines int64) ([]byte, error)
+	// LoadPodLogs loads the logs of the pod container.
+	LoadPodLogs(c context.Context, obj *status.Object, container string, opts PodLogOptions) ([]byte, error)
 
 	// LoadResource loads the resource based on its group resource, namespace and name
 	LoadResource(ctx context.Context, gvr schema.GroupResource, namespace string, name string) ([]*status.Object, error)
@@ -56,6 +64,8 @@ type Evaluator struct {
 	analyzers      []Analyzer
 	loader         Loader
 	analyzersCache map[types.UID]Analyzer
+	podLogOptions  PodLogOptions
+	explain        bool
 
 	cache              map[types.UID]*status.Object         // mapping of UID to the object
 	nsCache            map[string]*nsCache                  // mapping of namespace to its cache
@@ -83,6 +93,29 @@ func NewEvaluator(analyzerInits []AnalyzerInit, loader Loader) *Evaluator {
 	return evaluator
 }
 
+// SetPodLogOptions sets which logs the analyzers load for the unhealthy
+// containers. The Previous option applies only to the restarted containers.
+func (e *Evaluator) SetPodLogOptions(opts PodLogOptions) {
+	e.podLogOptions = opts
+}
+
+// PodLogOptions returns the options for loading the logs of the unhealthy
+// containers, with DefaultPodLogLines unless set otherwise.
+func (e *Evaluator) PodLogOptions() PodLogOptions {
+	opts := e.podLogOptions
+	if opts.TailLines <= 0 {
+		opts.TailLines = DefaultPodLogLines
+	}
+	return opts
+}
+
+// SetExplain turns on recording the provenance of the statuses, i.e. which
+// analyzer and rule determined the result. It's off by default, as the
+// provenance is needed only to explain the verdicts to the users.
+func (e *Evaluator) SetExplain(explain bool) {
+	e.explain = explain
+}
+
 // Filter returns the objects from the cache that match the matcher.
 // It expects the objects to be in the cache. This methods is intended
 // to run during evaluation of the Load method in the following order:
@@ -155,7 +188,7 @@ func (e *Evaluator) Eval(ctx context.Context, obj *status.Object) status.ObjectS
 		e.updateCache(obj)
 	}
 
-	return analyzer.Analyze(ctx, updatedObj)
+	return e.analyze(ctx, analyzer, updatedObj)
 }
 
 // EvalQuery loads the objects specified by the query and runs the analyzer.
@@ -167,7 +200,24 @@ func (e *Evaluator) EvalQuery(ctx context.Context, q QuerySpec, analyzer Analyze
 		return nil, err
 	}
 
-	return e.analyzeObjects(ctx, objects, analyzer), nil
+	ret := e.analyzeObjects(ctx, objects, analyzer)
+	if rq, ok := q.(RelationQuerySpec); ok {
+		for i := range ret {
+			ret[i].Relation = rq.Relation()
+		}
+	}
+	return ret, nil
+}
+
+// Get loads a single object by its kind, namespace and name, without
+// listing the whole kind. The NotFound API error is returned when the object
+// doesn't exist.
+func (e *Evaluator) Get(ctx context.Context, gk schema.GroupKind, ns, name string) (*status.Object, error) {
+	obj := &status.Object{}
+	obj.SetGroupVersionKind(gk.WithVersion(""))
+	obj.Namespace = ns
+	obj.Name = name
+	return e.loader.Get(ctx, obj)
 }
 
 func (e *Evaluator) ResourceToKind(gr schema.GroupResource) schema.GroupVersionKind {
@@ -176,8 +226,14 @@ func (e *Evaluator) ResourceToKind(gr schema.GroupResource) schema.GroupVersionK
 
 // Load loads the objects specified by the query.
 func (e *Evaluator) Load(ctx context.Context, q QuerySpec) ([]*status.Object, error) {
-	if e.getNsCache(q.Namespace()).updateMatcher(q.GroupKindMatcher()) {
-		e.loadNamespace(ctx, q.Namespace())
+	nsCache := e.getNsCache(q.Namespace())
+	matcher := nsCache.matcher
+	if nsCache.updateMatcher(q.GroupKindMatcher()) {
+		if err := e.loadNamespace(ctx, q.Namespace()); err != nil {
+			// Restore the matcher so that the next query tries loading again.
+			nsCache.matcher = matcher
+			return nil, err
+		}
 	}
 
 	objects := q.Eval(ctx, e)
@@ -254,11 +310,85 @@ func (e *Evaluator) analyzeObjects(ctx context.Context, objects []*status.Object
 		} else {
 			a = analyzer
 		}
-		ret = append(ret, a.Analyze(ctx, obj))
+		ret = append(ret, e.analyze(ctx, a, obj))
 	}
 	return ret
 }
 
+// analyze runs the analyzer on the object, honoring the overrides
+// set via the object annotations.
+func (e *Evaluator) analyze(ctx context.Context, a Analyzer, obj *status.Object) status.ObjectStatus {
+	if isIgnored(obj) {
+		return status.IgnoredStatus(obj)
+	}
+
+	os := a.Analyze(ctx, obj)
+	if e.explain {
+		os = withProvenance(a, os)
+	} else {
+		os = withoutProvenance(os)
+	}
+	return applyExpectedResult(obj, os)
+}
+
+// withProvenance fills in the analyzer into the provenance of the object
+// status and its conditions, if the analyzer didn't set it itself.
+func withProvenance(a Analyzer, os status.ObjectStatus) status.ObjectStatus {
+	analyzerName := fmt.Sprintf("%T", a)
+	if named, ok := a.(NamedAnalyzer); ok {
+		analyzerName = named.Name()
+	}
+
+	prov := status.Provenance{}
+	if os.ObjStatus.Provenance != nil {
+		prov = *os.ObjStatus.Provenance
+	}
+	if prov.Analyzer == "" {
+		prov.Analyzer = analyzerName
+	}
+	os.ObjStatus.Provenance = &prov
+
+	for i, cond := range os.Conditions {
+		if cond.CondStatus == nil {
+			continue
+		}
+		condStatus := *cond.CondStatus
+		condProv := status.Provenance{Reason: "synthetic condition"}
+		if condStatus.Provenance != nil {
+			condProv = *condStatus.Provenance
+		}
+		if condProv.Analyzer == "" {
+			condProv.Analyzer = analyzerName
+		}
+		condStatus.Provenance = &condProv
+		os.Conditions[i].CondStatus = &condStatus
+	}
+	return os
+}
+
+// withoutProvenance drops the provenance set by the analyzer, so that it's
+// not reported when not asked for. The sub-statuses are processed as well,
+// as the analyzers build some of them directly (e.g. the pod containers).
+func withoutProvenance(os status.ObjectStatus) status.ObjectStatus {
+	os.ObjStatus.Provenance = nil
+	for i, cond := range os.Conditions {
+		if cond.CondStatus == nil || cond.CondStatus.Provenance == nil {
+			continue
+		}
+		condStatus := *cond.CondStatus
+		condStatus.Provenance = nil
+		os.Conditions[i].CondStatus = &condStatus
+	}
+	if len(os.SubStatuses) > 0 {
+		subStatuses := make([]status.ObjectStatus, len(os.SubStatuses))
+		for i, sub := range os.SubStatuses {
+			subStatuses[i] = withoutProvenance(sub)
+		}
+		os.SubStatuses = subStatuses
+	}
+	return os
+}
+
 func (e *Evaluator) updateCache(obj *status.Object) bool {
 	if _, found := e.cache[obj.UID]; found {
 		return false
@@ -3,8 +3,11 @@ package eval
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -19,6 +22,10 @@ type FakeLoader struct {
 	nsCache map[string]*nsCache
 	podLogs map[string]string
 
+	// loadErrors simulate failures (e.g. forbidden access) when loading
+	// the kinds in the namespace.
+	loadErrors map[string]map[schema.GroupKind]error
+
 	// baseTime is used to replace the datetime data
 	// Given we focus mainly on relative values, we want the relative time
 	// to be stable so that we can use the values in the tests. By defualt, it
@@ -28,15 +35,32 @@ type FakeLoader struct {
 
 func NewFakeLoader() *FakeLoader {
 	return &FakeLoader{
-		cache:    make(map[types.UID]*status.Object),
-		nsCache:  make(map[string]*nsCache),
-		podLogs:  make(map[string]string),
-		baseTime: time.Now().UTC().Add(-24 * time.Hour),
+		cache:      make(map[types.UID]*status.Object),
+		nsCache:    make(map[string]*nsCache),
+		podLogs:    make(map[string]string),
+		loadErrors: make(map[string]map[schema.GroupKind]error),
+		baseTime:   time.Now().UTC().Add(-24 * time.Hour),
 	}
 }
 
 func (l *FakeLoader) Load(ctx context.Context, ns string, matcher GroupKindMatcher, exclude []schema.GroupKind) ([]*status.Object, error) {
+	for gk, err := range l.loadErrors[ns] {
+		if matcher.Match(gk) && !slices.Contains(exclude, gk) {
+			return nil, err
+		}
+	}
+
 	var ret []*status.Object
+	if ns == NamespaceAll {
+		for ns := range l.nsCache {
+			if ns != NamespaceAll {
+				objects, _ := l.Load(ctx, ns, matcher, exclude)
+				ret = append(ret, objects...)
+			}
+		}
+		return ret, nil
+	}
+
 	nsCache := l.getNsCache(ns)
 	for gk, objects := range nsCache.objects {
 		if matcher.Match(gk) {
@@ -68,12 +92,26 @@ func (l *FakeLoader) LoadResourceBySelector(ctx context.Context, gr schema.Group
 	return nil, nil
 }
 
-func (l *FakeLoader) LoadPodLogs(ctx context.Context, obj *status.Object, container string, tailLines int64) ([]byte, error) {
-	logs := l.podLogs[fmt.Sprintf("%s-%s-%s", obj.Namespace, obj.Name, container)]
-	return []byte(logs), nil
+func (l *FakeLoader) LoadPodLogs(ctx context.Context, obj *status.Object, container string, opts PodLogOptions) ([]byte, error) {
+	key := fmt.Sprintf("%s-%s-%s", obj.Namespace, obj.Name, container)
+	if opts.Previous {
+		key += "-previous"
+	}
+	lines := strings.SplitAfter(l.podLogs[key], "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if opts.TailLines > 0 && int64(len(lines)) > opts.TailLines {
+		lines = lines[int64(len(lines))-opts.TailLines:]
+	}
+	return []byte(strings.Join(lines, "")), nil
 }
 
 func (l *FakeLoader) Get(ctx context.Context, obj *status.Object) (*status.Object, error) {
+	if obj.UID == "" {
+		return l.getByName(obj)
+	}
+
 	obj, found := l.cache[obj.UID]
 	if !found {
 		return nil, fmt.Errorf("Object %v not found", obj)
@@ -82,6 +120,21 @@ func (l *FakeLoader) Get(ctx context.Context, obj *status.Object) (*status.Objec
 	return obj, nil
 }
 
+// getByName looks the object up by its kind, namespace and name, as when
+// getting it from the API server.
+func (l *FakeLoader) getByName(obj *status.Object) (*status.Object, error) {
+	gk := obj.GroupVersionKind().GroupKind()
+	if err, found := l.loadErrors[obj.Namespace][gk]; found {
+		return nil, err
+	}
+	for _, cached := range l.getNsCache(obj.Namespace).objects[gk] {
+		if cached.Name == obj.Name {
+			return cached, nil
+		}
+	}
+	return nil, apierrors.NewNotFound(schema.GroupResource{Group: gk.Group, Resource: gk.Kind}, obj.Name)
+}
+
 func (l *FakeLoader) Register(objects ...unstructured.Unstructured) ([]*status.Object, error) {
 	var ret []*status.Object
 	for _, uo := range objects {
@@ -107,6 +160,20 @@ func (f *FakeLoader) RegisterPodLogs(namespace, pod, container, logs string) {
 	f.podLogs[fmt.Sprintf("%s-%s-%s", namespace, pod, container)] = logs
 }
 
+// RegisterPreviousPodLogs registers the logs of the previous container instance.
+func (f *FakeLoader) RegisterPreviousPodLogs(namespace, pod, container, logs string) {
+	f.podLogs[fmt.Sprintf("%s-%s-%s-previous", namespace, pod, container)] = logs
+}
+
+// RegisterLoadError makes loading the kind in the namespace fail with the error.
+// Use NamespaceAll to simulate failures when listing across all namespaces.
+func (f *FakeLoader) RegisterLoadError(namespace string, gk schema.GroupKind, err error) {
+	if f.loadErrors[namespace] == nil {
+		f.loadErrors[namespace] = make(map[schema.GroupKind]error)
+	}
+	f.loadErrors[namespace][gk] = err
+}
+
 func (l *FakeLoader) getNsCache(ns string) *nsCache {
 	if l.nsCache[ns] == nil {
 		l.nsCache[ns] = newNsCache()
@@ -0,0 +1,60 @@
+package eval
+
+import (
+	"slices"
+	"sync"
+)
+
+// MergeLatest combines updates from multiple channels, e.g. from pollers
+// running against different clusters. Each update produces a combined value
+// of the latest updates from all the channels, in the order of the channels,
+// so that a slow channel doesn't hold back the others. The channels without
+// any update yet contribute the pending value for their index (the zero value
+// if pending is nil), closed channels without any update the zero value.
+//
+// The returned channel is closed once all the input channels are closed.
+func MergeLatest[T any](chans []<-chan T, pending func(i int) T, combine func([]T) T) <-chan T {
+	type indexedUpdate struct {
+		i      int
+		update T
+		closed bool
+	}
+
+	in := make(chan indexedUpdate)
+	var wg sync.WaitGroup
+	for i, ch := range chans {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for update := range ch {
+				in <- indexedUpdate{i: i, update: update}
+			}
+			in <- indexedUpdate{i: i, closed: true}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(in)
+	}()
+
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		latest := make([]T, len(chans))
+		if pending != nil {
+			for i := range latest {
+				latest[i] = pending(i)
+			}
+		}
+		received := make([]bool, len(chans))
+		for u := range in {
+			if u.closed && received[u.i] {
+				continue
+			}
+			latest[u.i] = u.update
+			received[u.i] = true
+			out <- combine(slices.Clone(latest))
+		}
+	}()
+	return out
+}
@@ -0,0 +1,37 @@
+package eval
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeLatest(t *testing.T) {
+	a := make(chan int)
+	b := make(chan int)
+	c := make(chan int)
+	pending := func(i int) int { return 9 }
+	out := MergeLatest([]<-chan int{a, b, c}, pending, func(values []int) int {
+		return values[0]*100 + values[1]*10 + values[2]
+	})
+
+	// Sent without waiting for the other channels.
+	a <- 1
+	assert.Equal(t, 199, <-out)
+
+	b <- 2
+	assert.Equal(t, 129, <-out)
+
+	// Closed without any update.
+	close(c)
+	assert.Equal(t, 120, <-out)
+
+	b <- 3
+	assert.Equal(t, 130, <-out)
+
+	close(a)
+	close(b)
+
+	_, open := <-out
+	assert.False(t, open)
+}
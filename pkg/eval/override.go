@@ -0,0 +1,50 @@
+package eval
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+// isIgnored returns true if the object opted out from the evaluation
+// via the status.AnnotationIgnore annotation.
+func isIgnored(obj *status.Object) bool {
+	return strings.EqualFold(obj.GetAnnotations()[status.AnnotationIgnore], "true")
+}
+
+// applyExpectedResult reports the object as Ok, if the result is not worse than
+// the one acknowledged by the status.AnnotationExpectedResult annotation.
+func applyExpectedResult(obj *status.Object, os status.ObjectStatus) status.ObjectStatus {
+	val, found := obj.GetAnnotations()[status.AnnotationExpectedResult]
+	if !found {
+		return os
+	}
+
+	expected, err := status.ParseResult(val)
+	if err != nil {
+		klog.V(2).ErrorS(err, "Invalid annotation value", "annotation", status.AnnotationExpectedResult, "object", obj)
+		return os
+	}
+
+	if os.ObjStatus.Result <= status.Ok || os.ObjStatus.Result > expected {
+		return os
+	}
+
+	if os.ObjStatus.Provenance != nil {
+		prov := *os.ObjStatus.Provenance
+		prov.Reason = fmt.Sprintf("%s is expected via the %s annotation, reported as %s (%s)",
+			os.ObjStatus.Result, status.AnnotationExpectedResult, status.Ok, prov.Reason)
+		os.ObjStatus.Provenance = &prov
+	}
+	os.ObjStatus.Result = status.Ok
+	os.ObjStatus.Status = status.Ok.String()
+	os.ObjStatus.Score = nil
+	os.Overrides = append(os.Overrides, status.Override{
+		Annotation: status.AnnotationExpectedResult,
+		Value:      val,
+	})
+	return os
+}
@@ -12,6 +12,7 @@ type StatusPoller struct {
 	interval  time.Duration
 	evaluator *Evaluator
 	objects   []*status.Object
+	queries   []QuerySpec
 	eventChan chan StatusUpdate
 }
 
@@ -24,9 +25,22 @@ func NewStatusPoller(interval time.Duration, evaluator *Evaluator, objects []*st
 	}
 }
 
+// NewQueryStatusPoller returns a poller evaluating the objects returned by
+// the queries. The queries are re-evaluated on every run, so that new objects
+// are picked up.
+func NewQueryStatusPoller(interval time.Duration, evaluator *Evaluator, queries []QuerySpec) *StatusPoller {
+	return &StatusPoller{
+		interval:  interval,
+		evaluator: evaluator,
+		queries:   queries,
+		eventChan: make(chan StatusUpdate),
+	}
+}
+
 type StatusUpdate struct {
 	Statuses []status.ObjectStatus
 	Error    error
+	Duration time.Duration // how long the evaluation took
 }
 
 // Start starts the poller and returns a channel that will receive status updates.
@@ -53,13 +67,31 @@ func (s *StatusPoller) Start(ctx context.Context) <-chan StatusUpdate {
 func (s *StatusPoller) run(ctx context.Context) {
 	// Reset the evaluator to clear the cache from previous run.
 	s.evaluator.Reset()
+	start := time.Now()
 
 	statuses := make([]status.ObjectStatus, 0, len(s.objects))
 	for _, obj := range s.objects {
 		statuses = append(statuses, s.evaluator.Eval(ctx, obj))
 	}
 
+	var err error
+	for _, q := range s.queries {
+		qStatuses, qErr := s.evaluator.EvalQuery(ctx, q, nil)
+		if qErr != nil {
+			err = qErr
+			continue
+		}
+		statuses = append(statuses, qStatuses...)
+	}
+
+	// The evaluation was interrupted: the results are incomplete.
+	if ctx.Err() != nil {
+		return
+	}
+
 	s.eventChan <- StatusUpdate{
 		Statuses: statuses,
+		Error:    err,
+		Duration: time.Since(start),
 	}
 }
@@ -3,9 +3,12 @@ package eval
 import (
 	"context"
 	"encoding/json"
+	"regexp"
 	"slices"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
@@ -43,6 +46,16 @@ type QuerySpec interface {
 	Eval(ctx context.Context, e *Evaluator) []*status.Object
 }
 
+// RelationQuerySpec is implemented by queries that find objects related
+// to another object. The relation is recorded in the statuses of the found
+// objects.
+type RelationQuerySpec interface {
+	QuerySpec
+
+	// Relation returns how the objects relate to the queried object.
+	Relation() status.Relation
+}
+
 // GroupKindMatcher allows specifying a set of kinds to match.
 type GroupKindMatcher struct {
 	// IncludeAll specifies whether all kinds should be included.
@@ -66,6 +79,17 @@ func NewGroupKindMatcherSingle(kind schema.GroupKind) GroupKindMatcher {
 	}
 }
 
+// ParseGroupKind resolves the kind from the resource or kind name as used
+// on the command line (e.g. "deployments.apps", "deploy" or "Deployment").
+func ParseGroupKind(mapper meta.RESTMapper, s string) (schema.GroupKind, error) {
+	gr := schema.ParseGroupResource(s)
+	gvk, err := mapper.KindFor(gr.WithVersion(""))
+	if err != nil {
+		return schema.GroupKind{}, err
+	}
+	return gvk.GroupKind(), nil
+}
+
 // Merge returns a new GroupKindMatcher that matches the union of the kinds
 // matched by the receiver and the other matcher.
 func (m GroupKindMatcher) Merge(other GroupKindMatcher) GroupKindMatcher {
@@ -192,6 +216,31 @@ func (qs KindQuerySpec) Eval(ctx context.Context, e *Evaluator) []*status.Object
 	return e.Filter(qs.Namespace(), qs.GK)
 }
 
+// TopLevelQuerySpec is a query that returns objects matching specific kinds
+// in the specified namespace that are not owned by any other object.
+type TopLevelQuerySpec struct {
+	GK GroupKindMatcher
+	Ns string
+}
+
+func (qs TopLevelQuerySpec) Namespace() string {
+	return qs.Ns
+}
+
+func (qs TopLevelQuerySpec) GroupKindMatcher() GroupKindMatcher {
+	return qs.GK
+}
+
+func (qs TopLevelQuerySpec) Eval(ctx context.Context, e *Evaluator) []*status.Object {
+	var ret []*status.Object
+	for _, obj := range e.Filter(qs.Namespace(), qs.GK) {
+		if len(obj.GetOwnerReferences()) == 0 {
+			ret = append(ret, obj)
+		}
+	}
+	return ret
+}
+
 // OwnerQuerySpec is a query that returns objects owned by the specified object.
 type OwnerQuerySpec struct {
 	Object *status.Object
@@ -212,6 +261,10 @@ func (qs OwnerQuerySpec) GroupKindMatcher() GroupKindMatcher {
 	return qs.GK
 }
 
+func (qs OwnerQuerySpec) Relation() status.Relation {
+	return status.RelationOwner
+}
+
 func (qs OwnerQuerySpec) Eval(ctx context.Context, e *Evaluator) []*status.Object {
 	candidates := e.Filter(qs.Namespace(), qs.GK)
 	return e.filterOwnedBy(qs.Object, candidates)
@@ -249,6 +302,10 @@ func (qs LabelQuerySpec) Namespace() string {
 	return qs.Object.GetNamespace()
 }
 
+func (qs LabelQuerySpec) Relation() status.Relation {
+	return status.RelationSelector
+}
+
 func (qs LabelQuerySpec) Eval(ctx context.Context, e *Evaluator) []*status.Object {
 	candidates := e.Filter(qs.Object.GetNamespace(), qs.GK)
 	var ret []*status.Object
@@ -266,17 +323,29 @@ func (qs LabelQuerySpec) Eval(ctx context.Context, e *Evaluator) []*status.Objec
 }
 
 func NewSelectorLabelQuerySpec(obj *status.Object, gk schema.GroupKind) LabelQuerySpec {
+	return NewSelectorLabelQuerySpecWithPath(obj, gk, "spec", "selector")
+}
+
+func NewSelectorLabelEqualityQuerySpec(obj *status.Object, gk schema.GroupKind) LabelQuerySpec {
+	return NewSelectorLabelEqualityQuerySpecWithPath(obj, gk, "spec", "selector")
+}
+
+// NewSelectorLabelQuerySpecWithPath works as NewSelectorLabelQuerySpec, reading
+// the set-based selector from the given path in the object.
+func NewSelectorLabelQuerySpecWithPath(obj *status.Object, gk schema.GroupKind, path ...string) LabelQuerySpec {
 	return LabelQuerySpec{
 		Object:   obj,
 		GK:       NewGroupKindMatcherSingle(gk),
-		Selector: buildSelectorOrNil(obj, labelSelectorSetBased, "spec", "selector")}
+		Selector: buildSelectorOrNil(obj, labelSelectorSetBased, path...)}
 }
 
-func NewSelectorLabelEqualityQuerySpec(obj *status.Object, gk schema.GroupKind) LabelQuerySpec {
+// NewSelectorLabelEqualityQuerySpecWithPath works as NewSelectorLabelEqualityQuerySpec,
+// reading the equality-based selector from the given path in the object.
+func NewSelectorLabelEqualityQuerySpecWithPath(obj *status.Object, gk schema.GroupKind, path ...string) LabelQuerySpec {
 	return LabelQuerySpec{
 		Object:   obj,
 		GK:       NewGroupKindMatcherSingle(gk),
-		Selector: buildSelectorOrNil(obj, labelSelectorEqualityBased, "spec", "selector"),
+		Selector: buildSelectorOrNil(obj, labelSelectorEqualityBased, path...),
 	}
 }
 
@@ -337,6 +406,10 @@ func (qs RefQuerySpec) Namespace() string {
 	return qs.Object.GetNamespace()
 }
 
+func (qs RefQuerySpec) Relation() status.Relation {
+	return status.RelationReference
+}
+
 func (qs RefQuerySpec) Eval(ctx context.Context, e *Evaluator) []*status.Object {
 	candidates := e.Filter(qs.Object.GetNamespace(), qs.GroupKindMatcher())
 	var ret []*status.Object
@@ -351,10 +424,40 @@ func (qs RefQuerySpec) Eval(ctx context.Context, e *Evaluator) []*status.Object
 	return ret
 }
 
+// DefaultPodLogLines is the number of log lines loaded when not specified.
+const DefaultPodLogLines = 5
+
+// PodLogOptions specifies which container logs to load.
+type PodLogOptions struct {
+	TailLines int64 // Number of lines from the end of the logs.
+	Previous  bool  // Load the logs of the previous (e.g. crashed) container instance.
+}
+
+// errorLogLineRe matches log lines that look like reporting an error.
+var errorLogLineRe = regexp.MustCompile(`(?i)\b(error|exception|panic|fatal|failed|traceback)\b`)
+
+// ErrorLogKeywords returns the index pairs of the error-looking keywords
+// in the text, as returned by regexp.FindAllStringIndex.
+func ErrorLogKeywords(s string) [][]int {
+	return errorLogLineRe.FindAllStringIndex(s, -1)
+}
+
+// FirstErrorLogLine returns the first line of the logs that looks like
+// reporting an error, or an empty string if there is none.
+func FirstErrorLogLine(logs string) string {
+	for _, line := range strings.Split(logs, "\n") {
+		if errorLogLineRe.MatchString(line) {
+			return strings.TrimSpace(line)
+		}
+	}
+	return ""
+}
+
 // PodLogQuerySpec is a query that returns logs of the specified pod.
 type PodLogQuerySpec struct {
 	Object    *status.Object
 	Container string
+	Options   PodLogOptions
 }
 
 func (qs PodLogQuerySpec) GroupKindMatcher() GroupKindMatcher {
@@ -368,7 +471,11 @@ func (qs PodLogQuerySpec) Namespace() string {
 
 func (qs PodLogQuerySpec) Eval(ctx context.Context, e *Evaluator) []*status.Object {
 	data := make(map[string]interface{}, 1)
-	logs, err := e.loader.LoadPodLogs(ctx, qs.Object, qs.Container, 5)
+	opts := qs.Options
+	if opts.TailLines <= 0 {
+		opts.TailLines = DefaultPodLogLines
+	}
+	logs, err := e.loader.LoadPodLogs(ctx, qs.Object, qs.Container, opts)
 	if err != nil {
 		klog.V(4).ErrorS(err, "Failed to get logs", "object", qs.Object)
 	} else {
@@ -71,8 +71,8 @@ func (l *RealLoader) Load(ctx context.Context, ns string, matcher GroupKindMatch
 	return ret, nil
 }
 
-func (l *RealLoader) LoadPodLogs(ctx context.Context, obj *status.Object, container string, tailLines int64) ([]byte, error) {
-	return l.client.podLogs(ctx, obj, container, tailLines)
+func (l *RealLoader) LoadPodLogs(ctx context.Context, obj *status.Object, container string, opts PodLogOptions) ([]byte, error) {
+	return l.client.podLogs(ctx, obj, container, opts)
 }
 
 func (l *RealLoader) ResourceToKind(gr schema.GroupResource) schema.GroupVersionKind {
@@ -416,15 +416,15 @@ func (c *client) get(ctx context.Context, obj *status.Object) (*unstructured.Uns
 	return unst, nil
 }
 
-func (c *client) podLogs(ctx context.Context, obj *status.Object, container string, tailLines int64) ([]byte, error) {
-	opts := &corev1.PodLogOptions{
+func (c *client) podLogs(ctx context.Context, obj *status.Object, container string, opts PodLogOptions) ([]byte, error) {
+	logOpts := &corev1.PodLogOptions{
 		Container: container,
 		Follow:    false,
-		Previous:  false,
-		TailLines: &tailLines,
+		Previous:  opts.Previous,
+		TailLines: &opts.TailLines,
 	}
 
-	return c.corev1client.Pods(obj.Namespace).GetLogs(obj.Name, opts).DoRaw(ctx)
+	return c.corev1client.Pods(obj.Namespace).GetLogs(obj.Name, logOpts).DoRaw(ctx)
 }
 
 func buildDynamicClient(c *rest.Config) (*dynamicclient.DynamicClient, error) {
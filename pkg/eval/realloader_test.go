@@ -6,6 +6,8 @@ import (
 	"github.com/inecas/kube-health/pkg/status"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -391,6 +393,28 @@ func TestLoadResource(t *testing.T) {
 	}
 }
 
+func TestEvaluatorGet(t *testing.T) {
+	gk := schema.GroupKind{Kind: "Pod"}
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{{Version: "v1"}})
+	mapper.Add(gk.WithVersion("v1"), meta.RESTScopeNamespace)
+	dynamic := createDynamicFakeClientWithObjects(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: test1Name, Namespace: testNS},
+	})
+	e := NewEvaluator(nil, &RealLoader{client: &client{dynamic: dynamic, mapper: mapper, resources: allTestResources}})
+
+	obj, err := e.Get(t.Context(), gk, testNS, test1Name)
+	assert.NoError(t, err)
+	assert.Equal(t, test1Name, obj.GetName())
+
+	_, err = e.Get(t.Context(), gk, testNS, "missing")
+	assert.True(t, apierrors.IsNotFound(err))
+
+	// The kind is not listed to find a single object.
+	for _, action := range dynamic.Actions() {
+		assert.Equal(t, "get", action.GetVerb())
+	}
+}
+
 func TestLoadResourceBySelector(t *testing.T) {
 	type testReource struct {
 		label, namespace string
@@ -7,11 +7,27 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/klog/v2"
+
+	"github.com/inecas/kube-health/pkg/analyze"
+	"github.com/inecas/kube-health/pkg/eval"
 )
 
 type Config struct {
 	// `yaml:"targets"`
 	Targets []Target
+	// AggregationPolicies overrides the policies for aggregating sub-objects
+	// per kind.
+	AggregationPolicies map[schema.GroupKind]analyze.AggregationPolicy
+	// Analyzers are paths to declarative analyzers definitions.
+	Analyzers []string
+	// PluginDirs are directories with analyzer plugins.
+	PluginDirs []string
+	// PathPlugins enables discovery of the analyzer plugins on PATH. The
+	// default plugin directory is always searched.
+	PathPlugins bool
+	// Contexts are the kubeconfig contexts of the clusters to monitor. The
+	// current context is used when empty.
+	Contexts []string
 }
 
 type Target struct {
@@ -27,6 +43,16 @@ type YAMLConfig struct {
 		Kinds    []string
 		// Namespaces []string
 	}
+	Aggregation []struct {
+		Kind   string
+		Policy string
+	}
+	Analyzers  []string
+	PluginDirs []string `yaml:"pluginDirs"`
+	Plugins    struct {
+		Enabled bool
+	}
+	Contexts []string
 }
 
 func ReadConfig(mapper meta.RESTMapper, path string) (Config, error) {
@@ -46,7 +72,7 @@ func ReadConfig(mapper meta.RESTMapper, path string) (Config, error) {
 	for _, t := range yamlCfg.Targets {
 		var kinds []schema.GroupKind
 		for _, k := range t.Kinds {
-			kind, err := parseKind(mapper, k)
+			kind, err := eval.ParseGroupKind(mapper, k)
 			if err != nil {
 				klog.ErrorS(err, "Failed to parse kind", "kind", k)
 				continue
@@ -60,14 +86,26 @@ func ReadConfig(mapper meta.RESTMapper, path string) (Config, error) {
 		})
 	}
 
-	return cfg, nil
-}
-
-func parseKind(mapper meta.RESTMapper, s string) (schema.GroupKind, error) {
-	gr := schema.ParseGroupResource(s)
-	gvk, err := mapper.KindFor(gr.WithVersion(""))
-	if err != nil {
-		return schema.GroupKind{}, err
+	for _, a := range yamlCfg.Aggregation {
+		kind, err := eval.ParseGroupKind(mapper, a.Kind)
+		if err != nil {
+			klog.ErrorS(err, "Failed to parse kind", "kind", a.Kind)
+			continue
+		}
+		policy, err := analyze.ParseAggregationPolicy(a.Policy)
+		if err != nil {
+			return cfg, err
+		}
+		if cfg.AggregationPolicies == nil {
+			cfg.AggregationPolicies = make(map[schema.GroupKind]analyze.AggregationPolicy)
+		}
+		cfg.AggregationPolicies[kind] = policy
 	}
-	return gvk.GroupKind(), nil
+
+	cfg.Analyzers = yamlCfg.Analyzers
+	cfg.PluginDirs = yamlCfg.PluginDirs
+	cfg.PathPlugins = yamlCfg.Plugins.Enabled
+	cfg.Contexts = yamlCfg.Contexts
+
+	return cfg, nil
 }
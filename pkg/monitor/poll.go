@@ -6,6 +6,7 @@ import (
 
 	"k8s.io/klog/v2"
 
+	"github.com/inecas/kube-health/pkg/analyze"
 	"github.com/inecas/kube-health/pkg/eval"
 	"github.com/inecas/kube-health/pkg/status"
 )
@@ -15,20 +16,32 @@ type MonitorPoller struct {
 	interval  time.Duration
 	evaluator *eval.Evaluator
 	cfg       Config
+	cluster   string
 	eventChan chan TargetsStatusUpdate
 }
 
 func NewMonitorPoller(interval time.Duration, evaluator *eval.Evaluator, cfg Config) *MonitorPoller {
+	return NewClusterMonitorPoller(interval, evaluator, cfg, "")
+}
+
+// NewClusterMonitorPoller creates a poller for one of multiple monitored
+// clusters. The cluster name is attached to all the reported statuses.
+func NewClusterMonitorPoller(interval time.Duration, evaluator *eval.Evaluator, cfg Config,
+	cluster string) *MonitorPoller {
 	return &MonitorPoller{
 		interval:  interval,
 		evaluator: evaluator,
 		cfg:       cfg,
+		cluster:   cluster,
 		eventChan: make(chan TargetsStatusUpdate),
 	}
 }
 
 type TargetStatuses struct {
-	Target   Target
+	Target Target
+	// Cluster is the name of the cluster the statuses come from. Empty when
+	// monitoring a single cluster.
+	Cluster  string
 	Statuses []status.ObjectStatus
 }
 
@@ -36,10 +49,24 @@ type TargetsStatusUpdate struct {
 	Statuses []TargetStatuses
 }
 
+// ToStatusUpdate flattens the statuses of all the targets. With multiple
+// clusters, the statuses are grouped per cluster.
 func (t TargetsStatusUpdate) ToStatusUpdate() eval.StatusUpdate {
 	statuses := make([]status.ObjectStatus, 0)
+	var clusters []string
+	clusterStatuses := make(map[string][]status.ObjectStatus)
 	for _, target := range t.Statuses {
-		statuses = append(statuses, target.Statuses...)
+		if target.Cluster == "" {
+			statuses = append(statuses, target.Statuses...)
+			continue
+		}
+		if _, found := clusterStatuses[target.Cluster]; !found {
+			clusters = append(clusters, target.Cluster)
+		}
+		clusterStatuses[target.Cluster] = append(clusterStatuses[target.Cluster], target.Statuses...)
+	}
+	for _, cluster := range clusters {
+		statuses = append(statuses, analyze.ClusterStatus(cluster, clusterStatuses[cluster], nil))
 	}
 	return eval.StatusUpdate{
 		Statuses: statuses,
@@ -71,7 +98,7 @@ func (s *MonitorPoller) run(ctx context.Context) {
 	// Reset the evaluator to clear the cache from previous run.
 	s.evaluator.Reset()
 
-	klog.V(1).Info("reloading health data")
+	klog.V(1).InfoS("reloading health data", "cluster", s.cluster)
 	start := time.Now()
 
 	statuses := make([]TargetStatuses, 0)
@@ -82,13 +109,13 @@ func (s *MonitorPoller) run(ctx context.Context) {
 			// TODO: add namespace support
 			//Namespace: target.Namespace,
 		}
-		s, err := s.evaluator.EvalQuery(ctx, querySpec, nil)
+		objs, err := s.evaluator.EvalQuery(ctx, querySpec, nil)
 		if err != nil {
 			klog.ErrorS(err, "failed to evaluate query", "query", querySpec)
 			continue
 		}
-		klog.V(3).InfoS("evaluated query", "query", querySpec, "objects", len(s))
-		statuses = append(statuses, TargetStatuses{Target: target, Statuses: s})
+		klog.V(3).InfoS("evaluated query", "query", querySpec, "objects", len(objs))
+		statuses = append(statuses, TargetStatuses{Target: target, Cluster: s.cluster, Statuses: objs})
 	}
 
 	klog.V(1).InfoS("health data reloaded", "duration", time.Since(start))
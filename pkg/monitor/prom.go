@@ -155,7 +155,7 @@ func (e *Exporter) digestUpdates() {
 		for _, part := range update.Statuses {
 			klog.V(2).InfoS("Received update", "objects", len(part.Statuses))
 			for _, status := range part.Statuses {
-				metric := statusToMetric(part.Target.Category, status)
+				metric := statusToMetric(part.Cluster, part.Target.Category, status)
 				klog.V(3).InfoS("Converted status to metric", "metric", metric)
 				metrics = append(metrics, metric)
 			}
@@ -175,7 +175,7 @@ func (e *Exporter) startServer(ctx context.Context) error {
 	return e.server.Start(ctx)
 }
 
-func statusToMetric(category string, objStatus status.ObjectStatus) Metric {
+func statusToMetric(cluster, category string, objStatus status.ObjectStatus) Metric {
 	status := objStatus.Status()
 	// We add "progressing" as extra result + expose the original value as result_details.
 	statusStr := strings.ToLower(status.Result.String())
@@ -183,6 +183,11 @@ func statusToMetric(category string, objStatus status.ObjectStatus) Metric {
 		statusStr = "progressing"
 	}
 
+	overrides := make([]string, 0, len(objStatus.Overrides))
+	for _, o := range objStatus.Overrides {
+		overrides = append(overrides, o.String())
+	}
+
 	return Metric{
 		Labels: prom.Labels{
 			"kind":      objStatus.Object.Kind,
@@ -191,6 +196,8 @@ func statusToMetric(category string, objStatus status.ObjectStatus) Metric {
 			"status":    statusStr,
 			"result":    strings.ToLower(status.Result.String()),
 			"category":  category,
+			"override":  strings.Join(overrides, ","),
+			"cluster":   cluster,
 		},
 		Value: resultToValue(status),
 	}
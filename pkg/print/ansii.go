@@ -16,9 +16,16 @@ const (
 type Color int
 
 var (
-	RED    Color = 31
-	GREEN  Color = 32
-	YELLOW Color = 33
+	RED     Color = 31
+	GREEN   Color = 32
+	YELLOW  Color = 33
+	BLUE    Color = 34
+	MAGENTA Color = 35
+
+	BRIGHT_RED     Color = 91
+	BRIGHT_YELLOW  Color = 93
+	BRIGHT_BLUE    Color = 94
+	BRIGHT_MAGENTA Color = 95
 )
 
 // SprintfWithColor formats according to the provided pattern and returns
@@ -0,0 +1,185 @@
+package print
+
+// Printers for annotating the CI jobs with the unhealthy objects.
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+// ciFinding is an unhealthy top-level object with its root cause.
+type ciFinding struct {
+	cluster string
+	root    status.ObjectStatus
+	cause   string // see rootCause
+	message string // message of the first unhealthy condition of the root cause
+	// causeKey identifies the root cause without the names of the objects,
+	// which are often generated (e.g. pods): the kind of the object and
+	// the type and reason of the condition.
+	causeKey string
+}
+
+func (f ciFinding) title() string {
+	title := fmt.Sprintf("%s %s", f.root.Status().Result, objectName(f.root.Object))
+	if f.cluster != "" {
+		title = fmt.Sprintf("[%s] %s", f.cluster, title)
+	}
+	return title
+}
+
+// ciFindings returns the unhealthy top-level objects, the most severe first.
+// The objects grouped under synthetic cluster objects are reported instead
+// of the clusters.
+func ciFindings(statuses []status.ObjectStatus) []ciFinding {
+	var findings []ciFinding
+	add := func(root status.ObjectStatus, cluster string) {
+		if !unhealthy(root.Status()) {
+			return
+		}
+		f := ciFinding{cluster: cluster, root: root, cause: rootCause(root)}
+		if cause, _ := findRootCause(root); cause.Object != nil {
+			f.causeKey = cause.Object.GroupVersionKind().GroupKind().String()
+			if cond, found := firstUnhealthyCondition(cause); found {
+				f.message = strings.TrimSpace(cond.Message)
+				f.causeKey += "|" + cond.Type + "|" + cond.Reason
+			}
+		}
+		findings = append(findings, f)
+	}
+
+	sortObjectsBySeverity(statuses)
+	for _, s := range statuses {
+		if s.Object == nil {
+			continue
+		}
+		if s.Object.IsCluster() {
+			sortObjectsBySeverity(s.SubStatuses)
+			for _, sub := range s.SubStatuses {
+				add(sub, s.Object.GetName())
+			}
+			continue
+		}
+		add(s, "")
+	}
+	return findings
+}
+
+// GitHubPrinter prints the unhealthy objects as GitHub Actions workflow
+// commands (::error and ::warning), shown as annotations of the job.
+// If SummaryFile is set (usually to $GITHUB_STEP_SUMMARY), the Markdown
+// report is appended to it, to be shown as the job summary.
+type GitHubPrinter struct {
+	PrintOpts   PrintOptions
+	SummaryFile string
+}
+
+func NewGitHubPrinter(opts PrintOptions, summaryFile string) *GitHubPrinter {
+	return &GitHubPrinter{PrintOpts: opts, SummaryFile: summaryFile}
+}
+
+func (p *GitHubPrinter) PrintStatuses(statuses []status.ObjectStatus, w io.Writer) {
+	for _, f := range ciFindings(statuses) {
+		command := "warning"
+		if f.root.Status().Result == status.Error {
+			command = "error"
+		}
+		msg := f.cause
+		if f.message != "" {
+			msg += "\n" + f.message
+		}
+		fmt.Fprintf(w, "::%s title=%s::%s\n", command, githubEscapeProperty(f.title()), githubEscapeData(msg))
+	}
+
+	if p.SummaryFile != "" {
+		if err := p.writeSummary(statuses); err != nil {
+			fmt.Fprintf(w, "::warning::%s\n", githubEscapeData("Failed to write the job summary: "+err.Error()))
+		}
+	}
+}
+
+func (p *GitHubPrinter) writeSummary(statuses []status.ObjectStatus) error {
+	// Other steps might have written to the summary already.
+	f, err := os.OpenFile(p.SummaryFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	NewMarkdownPrinter(p.PrintOpts).PrintStatuses(statuses, f)
+	return f.Close()
+}
+
+// githubEscapeData escapes the message of the workflow command.
+func githubEscapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// githubEscapeProperty escapes the parameter values of the workflow command.
+func githubEscapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// codeQualityIssue is an entry of the GitLab Code Quality report.
+type codeQualityIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    codeQualityLocation `json:"location"`
+}
+
+type codeQualityLocation struct {
+	Path  string           `json:"path"`
+	Lines codeQualityLines `json:"lines"`
+}
+
+type codeQualityLines struct {
+	Begin int `json:"begin"`
+}
+
+// GitLabPrinter prints the unhealthy objects as a GitLab Code Quality report,
+// shown in the merge request widget. The objects are not backed by files in
+// the repository: the location path identifies the object instead.
+type GitLabPrinter struct{}
+
+func NewGitLabPrinter() *GitLabPrinter {
+	return &GitLabPrinter{}
+}
+
+func (p *GitLabPrinter) PrintStatuses(statuses []status.ObjectStatus, w io.Writer) {
+	issues := []codeQualityIssue{}
+	for _, f := range ciFindings(statuses) {
+		severity := "minor"
+		if f.root.Status().Result == status.Error {
+			severity = "critical"
+		}
+		path := objectName(f.root.Object)
+		if f.cluster != "" {
+			path = f.cluster + "/" + path
+		}
+		// The fingerprint needs to be stable across runs, so that GitLab can
+		// tell the new issues from the existing ones.
+		sum := sha256.Sum256([]byte(path + "|" + f.causeKey))
+		issues = append(issues, codeQualityIssue{
+			Description: fmt.Sprintf("%s: %s", f.title(), f.cause),
+			CheckName:   "kube-health",
+			Fingerprint: hex.EncodeToString(sum[:]),
+			Severity:    severity,
+			Location: codeQualityLocation{
+				Path:  path,
+				Lines: codeQualityLines{Begin: 1},
+			},
+		})
+	}
+
+	out, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Fprintln(w, string(out))
+}
@@ -0,0 +1,41 @@
+package print
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+func gitLabIssues(t *testing.T, statuses []status.ObjectStatus) []codeQualityIssue {
+	sb := &strings.Builder{}
+	NewGitLabPrinter().PrintStatuses(statuses, sb)
+	var issues []codeQualityIssue
+	require.NoError(t, json.Unmarshal([]byte(sb.String()), &issues))
+	return issues
+}
+
+func TestGitLabPrinterFingerprint(t *testing.T) {
+	deployment := func(pod, reason string) []status.ObjectStatus {
+		p := testStatus("Pod", pod, status.Error)
+		p.Conditions = []status.ConditionStatus{testCondition("Ready", reason, "", status.Error)}
+		return []status.ObjectStatus{testStatus("Deployment", "web", status.Error, p)}
+	}
+
+	issues := gitLabIssues(t, deployment("web-5d8f7c9b6-x2k4p", "ContainersNotReady"))
+	require.Len(t, issues, 1)
+	assert.Equal(t, "Error default/Deployment/web: Pod/web-5d8f7c9b6-x2k4p (ContainersNotReady)", issues[0].Description)
+	assert.Equal(t, "default/Deployment/web", issues[0].Location.Path)
+
+	// The generated name of the pod doesn't change the fingerprint.
+	recreated := gitLabIssues(t, deployment("web-5d8f7c9b6-q9z7m", "ContainersNotReady"))
+	assert.Equal(t, issues[0].Fingerprint, recreated[0].Fingerprint)
+
+	// A different problem does.
+	other := gitLabIssues(t, deployment("web-5d8f7c9b6-x2k4p", "PodCompleted"))
+	assert.NotEqual(t, issues[0].Fingerprint, other[0].Fingerprint)
+}
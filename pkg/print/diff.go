@@ -0,0 +1,192 @@
+package print
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/inecas/kube-health/pkg/diff"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+var (
+	diffConditionsCols = []Column{
+		objectIndentCol,
+		{
+			Header:   "CONDITION",
+			Width:    40,
+			FormatFn: FormatFn(formatConditionDiffType),
+		},
+		{
+			Header:   "REASON",
+			Width:    30,
+			FormatFn: FormatFn(formatConditionDiffReason),
+		},
+	}
+)
+
+// changeMarker is the prefix of the changed lines, as known from the diff tool.
+func changeMarker(c diff.Change) string {
+	switch c {
+	case diff.Added:
+		return "+"
+	case diff.Removed:
+		return "-"
+	case diff.Changed:
+		return "~"
+	default:
+		return " "
+	}
+}
+
+func formatConditionDiffType(o PrintOptions, d diff.ConditionDiff) string {
+	formatSide := func(cond status.ConditionStatus) string {
+		ret := string(cond.Condition.Status)
+		if o.Color {
+			if color, setColor := statusColor(o, cond.Status()); setColor {
+				ret = SprintfWithColor(color, "%s", ret)
+			}
+		} else if cond.CondStatus.Result > status.Ok {
+			ret = fmt.Sprintf("%s (%s)", ret, cond.CondStatus.Result)
+		}
+		return ret
+	}
+
+	var value string
+	switch d.Change {
+	case diff.Added:
+		value = formatSide(*d.New)
+	case diff.Removed:
+		value = formatSide(*d.Old)
+	default:
+		value = formatSide(*d.Old)
+		if d.Change == diff.Changed {
+			value += " -> " + formatSide(*d.New)
+		}
+	}
+	return fmt.Sprintf("%s %s=%s", changeMarker(d.Change), d.Type, value)
+}
+
+func formatConditionDiffReason(o PrintOptions, d diff.ConditionDiff) string {
+	switch {
+	case d.New == nil:
+		return d.Old.Reason
+	case d.Old == nil || d.Old.Reason == d.New.Reason:
+		return d.New.Reason
+	default:
+		return fmt.Sprintf("%s -> %s", d.Old.Reason, d.New.Reason)
+	}
+}
+
+func formatObjectDiff(o PrintOptions, d diff.ObjectDiff, root, printGroups bool) string {
+	var text string
+	switch d.Change {
+	case diff.Removed:
+		text = formatObject(o, *d.Old, root, printGroups)
+	case diff.Changed:
+		text = fmt.Sprintf("%s -> %s", formatStatus(o, *d.Old), formatObject(o, *d.New, root, printGroups))
+	default:
+		text = formatObject(o, *d.New, root, printGroups)
+	}
+	return fmt.Sprintf("%s %s", changeMarker(d.Change), text)
+}
+
+// DiffPrinter prints the differences between two evaluations in the same
+// tree format as the TreePrinter. Only the changed objects and conditions
+// are shown, unless PrintOptions.ShowOk is set.
+type DiffPrinter struct {
+	*TreePrinter
+}
+
+func NewDiffPrinter(opts PrintOptions) *DiffPrinter {
+	return &DiffPrinter{TreePrinter: NewTreePrinter(opts)}
+}
+
+func (p *DiffPrinter) PrintDiff(diffs []diff.ObjectDiff, w io.Writer) {
+	diffs = p.visible(diffs)
+	if len(diffs) == 0 {
+		p.printf(w, "No changes\n")
+		return
+	}
+
+	p.printHeader(w, diffConditionsCols)
+	for _, d := range diffs {
+		prefixTail := ""
+		subDiffs := p.visible(d.SubObjects)
+		if len(subDiffs) > 0 {
+			prefixTail = "│ "
+		}
+		p.printObjectDiff(w, d, "", prefixTail)
+		p.printSubTreeDiff(w, subDiffs, "")
+	}
+
+	var added, removed, changed int
+	countChanges(diffs, &added, &removed, &changed)
+	p.printf(w, "\n%d added, %d removed, %d changed\n", added, removed, changed)
+}
+
+// visible filters the diffs to be printed.
+func (p *DiffPrinter) visible(diffs []diff.ObjectDiff) []diff.ObjectDiff {
+	if p.PrintOpts.ShowOk {
+		return diffs
+	}
+	var ret []diff.ObjectDiff
+	for _, d := range diffs {
+		if d.HasChanges() {
+			ret = append(ret, d)
+		}
+	}
+	return ret
+}
+
+func (p *DiffPrinter) printObjectDiff(w io.Writer, d diff.ObjectDiff, prefixHead, prefixTail string) {
+	p.printf(w, "%s%s\n", prefixHead, formatObjectDiff(p.PrintOpts, d, prefixHead == "", p.PrintOpts.ShowGroup))
+
+	for _, c := range d.Conditions {
+		if c.Change == diff.Unchanged && !p.PrintOpts.ShowOk {
+			continue
+		}
+		p.printRow(w, formatRow(diffConditionsCols, p.PrintOpts, c), prefixTail, prefixTail)
+		if c.New != nil && c.Change != diff.Unchanged &&
+			(c.New.Status().Result > status.Ok || c.New.Status().Progressing) {
+			p.printRow(w, formatRow(messageCols(p.PrintOpts), p.PrintOpts, *c.New), prefixTail, prefixTail)
+		}
+	}
+}
+
+func (p *DiffPrinter) printSubTreeDiff(w io.Writer, diffs []diff.ObjectDiff, prefix string) {
+	for j, d := range diffs {
+		var newPrefixHead, newPrefixTail, newPrefix string
+		if j < len(diffs)-1 {
+			newPrefixHead = `├─ `
+			newPrefixTail = `│  `
+			newPrefix = `│  `
+		} else {
+			newPrefixHead = `└─ `
+			newPrefixTail = "   "
+			newPrefix = "   "
+		}
+
+		subDiffs := p.visible(d.SubObjects)
+		if len(subDiffs) > 0 {
+			// Add an extra level of indentation if there are subresources to print.
+			newPrefixTail += "│ "
+		}
+
+		p.printObjectDiff(w, d, prefix+newPrefixHead, prefix+newPrefixTail)
+		p.printSubTreeDiff(w, subDiffs, prefix+newPrefix)
+	}
+}
+
+func countChanges(diffs []diff.ObjectDiff, added, removed, changed *int) {
+	for _, d := range diffs {
+		switch d.Change {
+		case diff.Added:
+			*added++
+		case diff.Removed:
+			*removed++
+		case diff.Changed:
+			*changed++
+		}
+		countChanges(d.SubObjects, added, removed, changed)
+	}
+}
@@ -0,0 +1,192 @@
+package print
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+// graphNode is an object in the health graph.
+type graphNode struct {
+	id     string
+	kind   string
+	name   string
+	status status.Status
+}
+
+// graphEdge connects a parent object with a sub-object. The label describes
+// how the sub-object was found (owner, selector or reference).
+type graphEdge struct {
+	from, to string
+	label    string
+}
+
+type graph struct {
+	nodes []graphNode
+	edges []graphEdge
+}
+
+// graphColors are the fill and stroke colors of the nodes per result.
+var graphColors = map[status.Result][2]string{
+	status.Unknown: {"#eaeef2", "#57606a"},
+	status.Ok:      {"#dafbe1", "#1a7f37"},
+	status.Warning: {"#fff8c5", "#9a6700"},
+	status.Error:   {"#ffebe9", "#cf222e"},
+}
+
+// buildGraph turns the status trees into a graph. Objects appearing under
+// multiple parents (e.g. a pod selected by several services) are represented
+// by a single node.
+func buildGraph(statuses []status.ObjectStatus) graph {
+	var g graph
+	ids := make(map[string]string)
+	edges := make(map[graphEdge]bool)
+
+	sortObjects(statuses)
+	for _, root := range statuses {
+		parents := make(map[string]string) // path key -> node id
+		walkTree(root, func(obj status.ObjectStatus, path []status.ObjectStatus) {
+			parentID := ""
+			if len(path) > 0 {
+				parentID = parents[pathKey(path)]
+			}
+			key := graphKey(obj.Object, parentID)
+			id, found := ids[key]
+			if !found {
+				id = fmt.Sprintf("n%d", len(g.nodes))
+				ids[key] = id
+				name := obj.Object.GetName()
+				if obj.Object.GetNamespace() != "" {
+					name = obj.Object.GetNamespace() + "/" + name
+				}
+				g.nodes = append(g.nodes, graphNode{
+					id:     id,
+					kind:   obj.Object.Kind,
+					name:   name,
+					status: obj.Status(),
+				})
+			}
+			parents[pathKey(append(path[:len(path):len(path)], obj))] = id
+
+			if parentID == "" {
+				return
+			}
+			e := graphEdge{from: parentID, to: id, label: string(obj.Relation)}
+			if !edges[e] {
+				edges[e] = true
+				g.edges = append(g.edges, e)
+			}
+		})
+	}
+	return g
+}
+
+// graphKey identifies the object in the graph. Objects with UID are
+// identified by it, the rest (e.g. containers) by the parent node and name.
+func graphKey(obj *status.Object, parentID string) string {
+	if obj.UID != "" {
+		return string(obj.UID)
+	}
+	return fmt.Sprintf("%s|%s/%s/%s", parentID, obj.Kind, obj.GetNamespace(), obj.GetName())
+}
+
+func pathKey(path []status.ObjectStatus) string {
+	parts := make([]string, 0, len(path))
+	for _, p := range path {
+		parts = append(parts, fmt.Sprintf("%s/%s/%s", p.Object.Kind, p.Object.GetNamespace(), p.Object.GetName()))
+	}
+	return strings.Join(parts, "|")
+}
+
+// DotPrinter prints the statuses as a Graphviz graph in the DOT language.
+// Nodes are colored by the result, progressing objects have a dashed border
+// and edges are labeled by the relation between the objects.
+type DotPrinter struct{}
+
+func NewDotPrinter() *DotPrinter {
+	return &DotPrinter{}
+}
+
+func (p *DotPrinter) PrintStatuses(statuses []status.ObjectStatus, w io.Writer) {
+	g := buildGraph(statuses)
+
+	fmt.Fprintln(w, "digraph health {")
+	fmt.Fprintln(w, "  rankdir=LR;")
+	fmt.Fprintln(w, `  node [shape=box, fontname="Helvetica"];`)
+	fmt.Fprintln(w, `  edge [fontname="Helvetica", fontsize=10];`)
+	for _, n := range g.nodes {
+		colors := graphColors[n.status.Result]
+		style := "filled,rounded"
+		if n.status.Progressing {
+			style += ",dashed"
+		}
+		label := fmt.Sprintf("%s\n%s\n%s", n.kind, n.name, transitionState(n.status))
+		fmt.Fprintf(w, "  %s [label=%s, style=%q, fillcolor=%q, color=%q];\n",
+			n.id, dotString(label), style, colors[0], colors[1])
+	}
+	for _, e := range g.edges {
+		if e.label != "" {
+			fmt.Fprintf(w, "  %s -> %s [label=%s];\n", e.from, e.to, dotString(e.label))
+		} else {
+			fmt.Fprintf(w, "  %s -> %s;\n", e.from, e.to)
+		}
+	}
+	fmt.Fprintln(w, "}")
+}
+
+func dotString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+// MermaidPrinter prints the statuses as a Mermaid flowchart, suitable for
+// embedding into Markdown documents. The styling follows the DotPrinter.
+type MermaidPrinter struct{}
+
+func NewMermaidPrinter() *MermaidPrinter {
+	return &MermaidPrinter{}
+}
+
+func (p *MermaidPrinter) PrintStatuses(statuses []status.ObjectStatus, w io.Writer) {
+	g := buildGraph(statuses)
+
+	fmt.Fprintln(w, "flowchart LR")
+	for _, n := range g.nodes {
+		label := strings.Join([]string{mermaidString(n.kind), mermaidString(n.name),
+			mermaidString(transitionState(n.status))}, "<br/>")
+		fmt.Fprintf(w, "  %s[\"%s\"]:::%s\n", n.id, label, n.status.Result)
+	}
+	for _, e := range g.edges {
+		if e.label != "" {
+			fmt.Fprintf(w, "  %s -->|\"%s\"| %s\n", e.from, mermaidString(e.label), e.to)
+		} else {
+			fmt.Fprintf(w, "  %s --> %s\n", e.from, e.to)
+		}
+	}
+	for _, res := range []status.Result{status.Unknown, status.Ok, status.Warning, status.Error} {
+		colors := graphColors[res]
+		fmt.Fprintf(w, "  classDef %s fill:%s,stroke:%s\n", res, colors[0], colors[1])
+	}
+	for _, n := range g.nodes {
+		if n.status.Progressing {
+			fmt.Fprintf(w, "  style %s stroke-dasharray: 5 5\n", n.id)
+		}
+	}
+}
+
+var mermaidReplacer = strings.NewReplacer(
+	"#", "#35;",
+	`"`, "#quot;",
+	"<", "#lt;",
+	">", "#gt;",
+)
+
+// mermaidString escapes the text to be used in a quoted label, using
+// the Mermaid entity codes.
+func mermaidString(s string) string {
+	return mermaidReplacer.Replace(s)
+}
@@ -0,0 +1,102 @@
+package print
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inecas/kube-health/internal/test"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+// testServices creates two services selecting the same pod.
+func testServices() []status.ObjectStatus {
+	pod := testStatus("Pod", "web-1", status.Error)
+	pod.Relation = status.RelationSelector
+	container := testContainer("web", status.Error)
+	container.ObjStatus.Progressing = true
+	pod.SubStatuses = []status.ObjectStatus{container}
+
+	return []status.ObjectStatus{
+		testStatus("Service", "web", status.Error, pod),
+		testStatus("Service", "web-canary", status.Error, pod),
+	}
+}
+
+func TestBuildGraph(t *testing.T) {
+	g := buildGraph(testServices())
+
+	var nodes []string
+	for _, n := range g.nodes {
+		nodes = append(nodes, n.id+" "+n.kind+" "+n.name)
+	}
+	// The pod is a single node, with an edge from each service. The container
+	// without UID is identified by its parent, and so it's shared too.
+	assert.Equal(t, []string{
+		"n0 Service default/web",
+		"n1 Pod default/web-1",
+		"n2 Container web",
+		"n3 Service default/web-canary",
+	}, nodes)
+	assert.Equal(t, []graphEdge{
+		{from: "n0", to: "n1", label: "selector"},
+		{from: "n1", to: "n2"},
+		{from: "n3", to: "n1", label: "selector"},
+	}, g.edges)
+}
+
+func TestDotPrinter(t *testing.T) {
+	sb := &strings.Builder{}
+	NewDotPrinter().PrintStatuses(testServices(), sb)
+	test.AssertStr(t, `
+digraph health {
+  rankdir=LR;
+  node [shape=box, fontname="Helvetica"];
+  edge [fontname="Helvetica", fontsize=10];
+  n0 [label="Service\ndefault/web\nError", style="filled,rounded", fillcolor="#ffebe9", color="#cf222e"];
+  n1 [label="Pod\ndefault/web-1\nError", style="filled,rounded", fillcolor="#ffebe9", color="#cf222e"];
+  n2 [label="Container\nweb\nError, progressing", style="filled,rounded,dashed", fillcolor="#ffebe9", color="#cf222e"];
+  n3 [label="Service\ndefault/web-canary\nError", style="filled,rounded", fillcolor="#ffebe9", color="#cf222e"];
+  n0 -> n1 [label="selector"];
+  n1 -> n2;
+  n3 -> n1 [label="selector"];
+}
+	`, sb.String())
+}
+
+func TestMermaidPrinter(t *testing.T) {
+	sb := &strings.Builder{}
+	NewMermaidPrinter().PrintStatuses(testServices(), sb)
+	test.AssertStr(t, `
+flowchart LR
+  n0["Service<br/>default/web<br/>Error"]:::Error
+  n1["Pod<br/>default/web-1<br/>Error"]:::Error
+  n2["Container<br/>web<br/>Error, progressing"]:::Error
+  n3["Service<br/>default/web-canary<br/>Error"]:::Error
+  n0 -->|"selector"| n1
+  n1 --> n2
+  n3 -->|"selector"| n1
+  classDef Unknown fill:#eaeef2,stroke:#57606a
+  classDef Ok fill:#dafbe1,stroke:#1a7f37
+  classDef Warning fill:#fff8c5,stroke:#9a6700
+  classDef Error fill:#ffebe9,stroke:#cf222e
+  style n2 stroke-dasharray: 5 5
+	`, sb.String())
+}
+
+func TestGraphEscaping(t *testing.T) {
+	assert.Equal(t, `"a\\b \"c\"\nd"`, dotString("a\\b \"c\"\nd"))
+	assert.Equal(t, `#lt;b#gt; #quot;c#quot; #35;quot;`, mermaidString(`<b> "c" #quot;`))
+
+	// The names from the status are escaped in the output.
+	obj := testStatus("Widget", `say "hi"`, status.Ok)
+	sb := &strings.Builder{}
+	NewDotPrinter().PrintStatuses([]status.ObjectStatus{obj}, sb)
+	require.Contains(t, sb.String(), `label="Widget\ndefault/say \"hi\"\nOk"`)
+
+	sb = &strings.Builder{}
+	NewMermaidPrinter().PrintStatuses([]status.ObjectStatus{obj}, sb)
+	require.Contains(t, sb.String(), `n0["Widget<br/>default/say #quot;hi#quot;<br/>Ok"]:::Ok`)
+}
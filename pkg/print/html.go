@@ -0,0 +1,173 @@
+package print
+
+import (
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+// htmlObject is the view of an object for the HTML template.
+type htmlObject struct {
+	Name        string
+	Result      string
+	Progressing bool
+	Open        bool
+	Error       string
+	Conditions  []htmlCondition
+	SubObjects  []htmlObject
+}
+
+type htmlCondition struct {
+	Type        string
+	Status      string
+	Result      string
+	Progressing bool
+	Age         string
+	Reason      string
+	Message     string
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>kube-health report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292f; }
+ul.tree, ul.tree ul { list-style: none; padding-left: 1.5em; }
+ul.tree { padding-left: 0; }
+ul.tree ul { border-left: 1px solid #d0d7de; }
+summary, .leaf { cursor: default; padding: 2px 0; }
+summary { cursor: pointer; }
+.name { font-family: ui-monospace, Menlo, Consolas, monospace; }
+.result { display: inline-block; min-width: 5.5em; padding: 0 4px; border-radius: 4px; font-weight: bold; text-align: center; }
+.Ok { background: #dafbe1; color: #1a7f37; }
+.Warning { background: #fff8c5; color: #9a6700; }
+.Error { background: #ffebe9; color: #cf222e; }
+.Unknown { background: #eaeef2; color: #57606a; }
+.progressing { border: 1px dashed #9a6700; }
+table { border-collapse: collapse; margin: 4px 0 8px 1.5em; font-size: 90%; }
+th, td { border: 1px solid #d0d7de; padding: 2px 8px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+td.message { white-space: pre-wrap; font-family: ui-monospace, Menlo, Consolas, monospace; max-width: 80em; }
+.error { color: #cf222e; margin-left: 1.5em; }
+</style>
+</head>
+<body>
+<h1>kube-health report</h1>
+<p>Generated at {{.Time}}</p>
+{{- if not .Objects}}
+<p>No objects found.</p>
+{{- else}}
+<ul class="tree">
+{{- range .Objects}}
+{{template "object" .}}
+{{- end}}
+</ul>
+{{- end}}
+</body>
+</html>
+{{define "status"}}<span class="result {{.Result}}{{if .Progressing}} progressing{{end}}">{{.Result}}</span>{{end}}
+{{define "object"}}<li>
+{{- if or .Conditions .SubObjects .Error}}
+<details{{if .Open}} open{{end}}>
+<summary>{{template "status" .}} <span class="name">{{.Name}}</span></summary>
+{{- if .Error}}
+<div class="error">{{.Error}}</div>
+{{- end}}
+{{- if .Conditions}}
+<table>
+<tr><th>Condition</th><th>Status</th><th>Result</th><th>Age</th><th>Reason</th><th>Message</th></tr>
+{{- range .Conditions}}
+<tr><td>{{.Type}}</td><td>{{.Status}}</td><td>{{template "status" .}}</td><td>{{.Age}}</td><td>{{.Reason}}</td><td class="message">{{.Message}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .SubObjects}}
+<ul>
+{{- range .SubObjects}}
+{{template "object" .}}
+{{- end}}
+</ul>
+{{- end}}
+</details>
+{{- else}}
+<div class="leaf">{{template "status" .}} <span class="name">{{.Name}}</span></div>
+{{- end}}
+</li>{{end}}
+`))
+
+// HTMLPrinter prints the statuses as a self-contained HTML page, with
+// the trees of objects expandable. The unhealthy objects are expanded by
+// default, the same way as the tree printer shows their details.
+type HTMLPrinter struct {
+	PrintOpts PrintOptions
+}
+
+func NewHTMLPrinter(opts PrintOptions) *HTMLPrinter {
+	return &HTMLPrinter{PrintOpts: opts}
+}
+
+func (p *HTMLPrinter) PrintStatuses(statuses []status.ObjectStatus, w io.Writer) {
+	data := struct {
+		Time    string
+		Objects []htmlObject
+	}{
+		Time: time.Now().Format(time.RFC3339),
+	}
+
+	sortObjectsBySeverity(statuses)
+	for _, s := range statuses {
+		if s.Object != nil {
+			data.Objects = append(data.Objects, p.toHTMLObject(s, true))
+		}
+	}
+
+	if err := htmlTemplate.Execute(w, data); err != nil {
+		panic(err)
+	}
+}
+
+func (p *HTMLPrinter) toHTMLObject(obj status.ObjectStatus, root bool) htmlObject {
+	s := obj.Status()
+	name := obj.Object.Kind + "/" + obj.Object.GetName()
+	if root {
+		name = objectName(obj.Object)
+	}
+	if p.PrintOpts.ShowGroup {
+		name += " [" + obj.Object.GroupVersionKind().Group + "]"
+	}
+
+	ret := htmlObject{
+		Name:        name,
+		Result:      s.Result.String(),
+		Progressing: s.Progressing,
+		Open:        p.PrintOpts.ShowOk || s.Result > status.Ok || s.Progressing,
+	}
+	if obj.ObjStatus.Err != nil {
+		ret.Error = obj.ObjStatus.Err.Error()
+	}
+	for _, cond := range obj.Conditions {
+		cs := cond.Status()
+		ret.Conditions = append(ret.Conditions, htmlCondition{
+			Type:        cond.Type,
+			Status:      string(cond.Condition.Status),
+			Result:      cs.Result.String(),
+			Progressing: cs.Progressing,
+			Age:         formatConditionAge(p.PrintOpts, cond),
+			Reason:      cond.Reason,
+			Message:     strings.TrimSpace(cond.Message),
+		})
+	}
+
+	sortObjects(obj.SubStatuses)
+	for _, sub := range obj.SubStatuses {
+		if sub.Object != nil {
+			ret.SubObjects = append(ret.SubObjects, p.toHTMLObject(sub, false))
+		}
+	}
+	return ret
+}
@@ -0,0 +1,83 @@
+package print
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/inecas/kube-health/internal/test"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+var htmlTimeRe = regexp.MustCompile(`Generated at .*</p>`)
+
+// htmlBody returns the body of the HTML report, without the styles and
+// the generation time.
+func htmlBody(out string) string {
+	out = htmlTimeRe.ReplaceAllString(out, "Generated at T</p>")
+	return out[strings.Index(out, "<body>"):]
+}
+
+func TestHTMLPrinter(t *testing.T) {
+	statuses := []status.ObjectStatus{testStatus("Service", "api", status.Ok), testDeployment()}
+
+	sb := &strings.Builder{}
+	NewHTMLPrinter(PrintOptions{}).PrintStatuses(statuses, sb)
+	test.AssertStr(t, `
+<body>
+<h1>kube-health report</h1>
+<p>Generated at T</p>
+<ul class="tree">
+<li>
+<details open>
+<summary><span class="result Error">Error</span> <span class="name">default/Deployment/web</span></summary>
+<ul>
+<li>
+<details open>
+<summary><span class="result Error">Error</span> <span class="name">ReplicaSet/web-1</span></summary>
+<ul>
+<li>
+<details>
+<summary><span class="result Ok">Ok</span> <span class="name">Pod/web-1-a</span></summary>
+<table>
+<tr><th>Condition</th><th>Status</th><th>Result</th><th>Age</th><th>Reason</th><th>Message</th></tr>
+<tr><td>Ready</td><td>False</td><td><span class="result Ok">Ok</span></td><td></td><td></td><td class="message"></td></tr>
+</table>
+</details>
+</li>
+<li>
+<details open>
+<summary><span class="result Error">Error</span> <span class="name">Pod/web-1-b</span></summary>
+<table>
+<tr><th>Condition</th><th>Status</th><th>Result</th><th>Age</th><th>Reason</th><th>Message</th></tr>
+<tr><td>Ready</td><td>False</td><td><span class="result Error">Error</span></td><td></td><td>ContainersNotReady</td><td class="message">containers with unready status: [web] &lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; &amp; more
+see | logs</td></tr>
+<tr><td>PodScheduled</td><td>False</td><td><span class="result Ok">Ok</span></td><td></td><td></td><td class="message"></td></tr>
+</table>
+</details>
+</li>
+</ul>
+</details>
+</li>
+</ul>
+</details>
+</li>
+<li>
+<div class="leaf"><span class="result Ok">Ok</span> <span class="name">default/Service/api</span></div>
+</li>
+</ul>
+</body>
+</html>
+	`, htmlBody(sb.String()))
+
+	sb = &strings.Builder{}
+	NewHTMLPrinter(PrintOptions{}).PrintStatuses(nil, sb)
+	test.AssertStr(t, `
+<body>
+<h1>kube-health report</h1>
+<p>Generated at T</p>
+<p>No objects found.</p>
+</body>
+</html>
+	`, htmlBody(sb.String()))
+}
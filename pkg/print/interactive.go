@@ -0,0 +1,763 @@
+package print
+
+// Interactive terminal UI for exploring the status trees.
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/inecas/kube-health/pkg/eval"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+// terminalControlRe matches the escape sequences and control characters
+// that could change the state of the terminal: CSI and OSC sequences, other
+// escapes and C0/C1 control characters except for the tabs and new lines.
+var terminalControlRe = regexp.MustCompile(
+	`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)?|\x1b[@-_]?|[\x00-\x08\x0b-\x1f\x7f\x{80}-\x{9f}]`)
+
+// LogFn fetches the logs of a container in the pod. The cluster is the name
+// of the Cluster object the pod is grouped under, empty for a single cluster.
+type LogFn func(ctx context.Context, cluster string, pod *status.Object, container string,
+	opts eval.PodLogOptions) (string, error)
+
+type interactiveView int
+
+const (
+	treeView interactiveView = iota
+	detailsView
+	logsView
+)
+
+type key string
+
+const (
+	keyUp       key = "up"
+	keyDown     key = "down"
+	keyLeft     key = "left"
+	keyRight    key = "right"
+	keyPageUp   key = "pgup"
+	keyPageDown key = "pgdown"
+	keyHome     key = "home"
+	keyEnd      key = "end"
+	keyEnter    key = "enter"
+	keyEsc      key = "esc"
+	keyQuit     key = "quit"
+)
+
+var keySequences = map[string]key{
+	"\x1b[A":  keyUp,
+	"\x1bOA":  keyUp,
+	"k":       keyUp,
+	"\x1b[B":  keyDown,
+	"\x1bOB":  keyDown,
+	"j":       keyDown,
+	"\x1b[D":  keyLeft,
+	"\x1bOD":  keyLeft,
+	"h":       keyLeft,
+	"\x1b[C":  keyRight,
+	"\x1bOC":  keyRight,
+	"\x1b[5~": keyPageUp,
+	"\x1b[6~": keyPageDown,
+	"\x1b[H":  keyHome,
+	"g":       keyHome,
+	"\x1b[F":  keyEnd,
+	"G":       keyEnd,
+	"\r":      keyEnter,
+	"\n":      keyEnter,
+	"\x1b":    keyEsc,
+	"q":       keyQuit,
+	"\x03":    keyQuit, // Ctrl+C in raw mode.
+}
+
+// treeLine is a single line of the tree view.
+type treeLine struct {
+	key         string
+	obj         status.ObjectStatus
+	parent      *status.Object
+	cluster     string
+	prefix      string
+	root        bool
+	hasChildren bool
+	expanded    bool
+}
+
+type logsResult struct {
+	key  string
+	logs string
+}
+
+// InteractivePrinter shows the status trees in an interactive terminal UI.
+// It allows expanding and collapsing sub-objects, filtering by result,
+// jumping between errors and showing the full conditions messages and pod
+// logs. The tree is updated as the updates arrive to the update channel.
+//
+// The input is expected to be a terminal in raw mode.
+type InteractivePrinter struct {
+	PrintOpts PrintOptions
+	in        io.Reader
+	out       io.Writer
+	// size returns the current width and height of the terminal.
+	size       func() (int, int)
+	updateChan <-chan eval.StatusUpdate
+	logFn      LogFn
+	logOpts    eval.PodLogOptions
+	callback   func([]status.ObjectStatus)
+
+	statuses  []status.ObjectStatus
+	err       error
+	updated   time.Time
+	finished  bool
+	expanded  map[string]bool
+	minResult status.Result
+	lines     []treeLine
+	cursor    int
+	offset    int
+
+	view       interactiveView
+	viewKey    string
+	viewLines  []string
+	viewOffset int
+	logsChan   chan logsResult
+	statusMsg  string
+}
+
+// NewInteractivePrinter creates the interactive UI. The logs of the pods are
+// fetched via logFn with logOpts, the Previous option being applied only to
+// the restarted containers.
+func NewInteractivePrinter(opts PrintOptions, in io.Reader, out io.Writer, size func() (int, int),
+	updateChan <-chan eval.StatusUpdate, logFn LogFn, logOpts eval.PodLogOptions,
+	callback func([]status.ObjectStatus)) *InteractivePrinter {
+	return &InteractivePrinter{
+		PrintOpts:  opts,
+		in:         in,
+		out:        out,
+		size:       size,
+		updateChan: updateChan,
+		logFn:      logFn,
+		logOpts:    logOpts,
+		callback:   callback,
+		expanded:   make(map[string]bool),
+		logsChan:   make(chan logsResult),
+	}
+}
+
+// Start runs the UI until the user quits or the context is canceled.
+func (p *InteractivePrinter) Start(ctx context.Context) {
+	keys := readKeys(p.in)
+
+	// Use the alternate screen and hide the cursor while running.
+	fmt.Fprintf(p.out, "%c[?1049h%c[?25l", ESC, ESC)
+	defer fmt.Fprintf(p.out, "%c[?25h%c[?1049l", ESC, ESC)
+
+	updateChan := p.updateChan
+	p.render()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case update, ok := <-updateChan:
+			if !ok {
+				updateChan = nil
+				p.finished = true
+				break
+			}
+			p.update(update)
+		case res := <-p.logsChan:
+			p.updateLogs(res)
+		case k, ok := <-keys:
+			if !ok || !p.handleKey(ctx, k) {
+				return
+			}
+		}
+		p.render()
+	}
+}
+
+// readKeys reads the key presses from the input. Escape sequences of special
+// keys are expected to arrive in a single read.
+func readKeys(in io.Reader) <-chan key {
+	ret := make(chan key)
+	go func() {
+		defer close(ret)
+		buf := make([]byte, 32)
+		for {
+			n, err := in.Read(buf)
+			if err != nil {
+				return
+			}
+			seq := string(buf[:n])
+			if k, found := keySequences[seq]; found {
+				ret <- k
+			} else {
+				ret <- key(seq)
+			}
+		}
+	}()
+	return ret
+}
+
+func (p *InteractivePrinter) update(update eval.StatusUpdate) {
+	p.err = update.Error
+	if update.Error == nil {
+		p.statuses = update.Statuses
+		p.updated = time.Now()
+		if p.callback != nil {
+			p.callback(update.Statuses)
+		}
+	}
+	p.refreshLines()
+
+	if p.view == detailsView {
+		if line, found := p.findLine(p.viewKey); found {
+			p.viewLines = p.details(line)
+		}
+	}
+}
+
+// handleKey processes the key press. It returns false when the UI should quit.
+func (p *InteractivePrinter) handleKey(ctx context.Context, k key) bool {
+	p.statusMsg = ""
+	if p.view != treeView {
+		return p.handleViewKey(k)
+	}
+
+	_, height := p.size()
+	page := max(height-3, 1)
+
+	switch k {
+	case keyQuit:
+		return false
+	case keyUp:
+		p.moveCursor(-1)
+	case keyDown:
+		p.moveCursor(1)
+	case keyPageUp:
+		p.moveCursor(-page)
+	case keyPageDown:
+		p.moveCursor(page)
+	case keyHome:
+		p.moveCursor(-len(p.lines))
+	case keyEnd:
+		p.moveCursor(len(p.lines))
+	case keyRight, " ", keyLeft:
+		p.toggle(k)
+	case "n":
+		p.jumpToError(1)
+	case "N", "p":
+		p.jumpToError(-1)
+	case "f":
+		p.cycleFilter()
+	case keyEnter:
+		if line, ok := p.currentLine(); ok {
+			p.openView(detailsView, line.key, p.details(line))
+		}
+	case "l":
+		if line, ok := p.currentLine(); ok {
+			p.showLogs(ctx, line)
+		}
+	}
+	return true
+}
+
+func (p *InteractivePrinter) handleViewKey(k key) bool {
+	_, height := p.size()
+	page := max(height-3, 1)
+
+	switch k {
+	case keyQuit, keyEsc, keyLeft:
+		p.view = treeView
+	case keyUp:
+		p.scrollView(-1)
+	case keyDown:
+		p.scrollView(1)
+	case keyPageUp, "b":
+		p.scrollView(-page)
+	case keyPageDown, " ":
+		p.scrollView(page)
+	case keyHome:
+		p.scrollView(-len(p.viewLines))
+	case keyEnd:
+		p.scrollView(len(p.viewLines))
+	}
+	return true
+}
+
+func (p *InteractivePrinter) currentLine() (treeLine, bool) {
+	if p.cursor < 0 || p.cursor >= len(p.lines) {
+		return treeLine{}, false
+	}
+	return p.lines[p.cursor], true
+}
+
+func (p *InteractivePrinter) findLine(key string) (treeLine, bool) {
+	for _, l := range p.lines {
+		if l.key == key {
+			return l, true
+		}
+	}
+	return treeLine{}, false
+}
+
+func (p *InteractivePrinter) moveCursor(delta int) {
+	p.cursor = max(min(p.cursor+delta, len(p.lines)-1), 0)
+}
+
+func (p *InteractivePrinter) scrollView(delta int) {
+	p.viewOffset = max(min(p.viewOffset+delta, len(p.viewLines)-1), 0)
+}
+
+func (p *InteractivePrinter) toggle(k key) {
+	line, ok := p.currentLine()
+	if !ok || !line.hasChildren {
+		return
+	}
+	switch k {
+	case keyRight:
+		p.expanded[line.key] = true
+	case keyLeft:
+		p.expanded[line.key] = false
+	default:
+		p.expanded[line.key] = !line.expanded
+	}
+	p.refreshLines()
+}
+
+// jumpToError moves the cursor to the next (or previous) object in the Error
+// state, expanding the collapsed objects on the way.
+func (p *InteractivePrinter) jumpToError(direction int) {
+	// Expand the whole tree to find the errors hidden in collapsed objects.
+	all := p.flatten(true)
+
+	current, _ := p.currentLine()
+	start := -1
+	for i, l := range all {
+		if l.key == current.key {
+			start = i
+			break
+		}
+	}
+
+	for i := 1; i <= len(all); i++ {
+		idx := ((start+direction*i)%len(all) + len(all)) % len(all)
+		if all[idx].obj.Status().Result != status.Error {
+			continue
+		}
+		// Expand the ancestors of the object.
+		for _, l := range all {
+			if strings.HasPrefix(all[idx].key, l.key+"|") {
+				p.expanded[l.key] = true
+			}
+		}
+		p.refreshLines()
+		if _, found := p.findLine(all[idx].key); found {
+			p.selectKey(all[idx].key)
+		}
+		return
+	}
+	p.statusMsg = "No errors found"
+}
+
+func (p *InteractivePrinter) cycleFilter() {
+	switch p.minResult {
+	case status.Unknown, status.Ok:
+		p.minResult = status.Warning
+	case status.Warning:
+		p.minResult = status.Error
+	default:
+		p.minResult = status.Unknown
+	}
+	p.refreshLines()
+}
+
+func (p *InteractivePrinter) openView(v interactiveView, key string, lines []string) {
+	p.view = v
+	p.viewKey = key
+	p.viewLines = lines
+	p.viewOffset = 0
+}
+
+// showLogs fetches the logs of the selected pod or container on the
+// background.
+func (p *InteractivePrinter) showLogs(ctx context.Context, line treeLine) {
+	var pod *status.Object
+	var containers []string
+	switch line.obj.Object.Kind {
+	case "Pod":
+		pod = line.obj.Object
+		containers = podContainers(line.obj)
+	case "Container":
+		if line.parent != nil {
+			pod = line.parent
+			containers = []string{line.obj.Object.Name}
+		}
+	}
+	if pod == nil || p.logFn == nil {
+		p.statusMsg = "Logs are available only for pods and containers"
+		return
+	}
+
+	p.openView(logsView, line.key, []string{"Loading logs..."})
+	go func() {
+		sb := &strings.Builder{}
+		for _, c := range containers {
+			opts := p.logOpts
+			opts.Previous = opts.Previous && restartedContainer(pod, c)
+			switch {
+			case opts.Previous:
+				fmt.Fprintf(sb, "==> %s (previous) <==\n", c)
+			case len(containers) > 1:
+				fmt.Fprintf(sb, "==> %s <==\n", c)
+			}
+			logs, err := p.logFn(ctx, line.cluster, pod, c, opts)
+			if err != nil {
+				fmt.Fprintf(sb, "Error loading logs: %s\n", err)
+				continue
+			}
+			sb.WriteString(logs)
+		}
+		if sb.Len() == 0 {
+			sb.WriteString("No logs available")
+		}
+		select {
+		case p.logsChan <- logsResult{key: line.key, logs: sb.String()}:
+		case <-ctx.Done():
+		}
+	}()
+}
+
+// restartedContainer checks the container of the pod has a terminated
+// previous instance, so that its logs can be loaded.
+func restartedContainer(pod *status.Object, container string) bool {
+	if pod.Unstructured == nil {
+		return false
+	}
+	for _, field := range []string{"initContainerStatuses", "containerStatuses"} {
+		statuses, _, _ := unstructured.NestedSlice(pod.Unstructured.Object, "status", field)
+		for _, s := range statuses {
+			m, ok := s.(map[string]interface{})
+			if !ok || m["name"] != container {
+				continue
+			}
+			_, found, _ := unstructured.NestedMap(m, "lastState", "terminated")
+			return found
+		}
+	}
+	return false
+}
+
+// updateLogs shows the fetched logs, unless the user left the logs view
+// in the meantime.
+func (p *InteractivePrinter) updateLogs(res logsResult) {
+	if p.view == logsView && p.viewKey == res.key {
+		p.viewLines = p.wrap(res.logs)
+	}
+}
+
+// podContainers returns the names of the pod containers, preferring the
+// Container sub-objects reported by the analyzer.
+func podContainers(pod status.ObjectStatus) []string {
+	var ret []string
+	for _, sub := range pod.SubStatuses {
+		if sub.Object != nil && sub.Object.Kind == "Container" {
+			ret = append(ret, sub.Object.Name)
+		}
+	}
+	if len(ret) > 0 || pod.Object.Unstructured == nil {
+		return ret
+	}
+
+	containers, _, _ := unstructured.NestedSlice(pod.Object.Unstructured.Object, "spec", "containers")
+	for _, c := range containers {
+		if m, ok := c.(map[string]interface{}); ok {
+			if name, ok := m["name"].(string); ok {
+				ret = append(ret, name)
+			}
+		}
+	}
+	return ret
+}
+
+// refreshLines rebuilds the tree lines, keeping the cursor at the same object.
+func (p *InteractivePrinter) refreshLines() {
+	current, _ := p.currentLine()
+	p.lines = p.flatten(false)
+	p.selectKey(current.key)
+}
+
+func (p *InteractivePrinter) selectKey(key string) {
+	for i, l := range p.lines {
+		if l.key == key {
+			p.cursor = i
+			return
+		}
+	}
+	p.moveCursor(0)
+}
+
+// visible decides whether the object passes the result filter. Objects with
+// visible sub-objects are kept to preserve the tree.
+func (p *InteractivePrinter) visible(obj status.ObjectStatus) bool {
+	if obj.Object == nil {
+		return false
+	}
+	if passesMinResult(obj.Status().Result, p.minResult) {
+		return true
+	}
+	for _, sub := range obj.SubStatuses {
+		if p.visible(sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// flatten turns the status trees into the lines to show. With expandAll,
+// the collapsed objects are included as well.
+func (p *InteractivePrinter) flatten(expandAll bool) []treeLine {
+	var ret []treeLine
+	objects := p.filterVisible(p.statuses)
+	sortObjectsBySeverity(objects)
+	for _, obj := range objects {
+		cluster := ""
+		if obj.Object.IsCluster() {
+			cluster = obj.Object.Name
+		}
+		ret = p.flattenObject(ret, obj, nil, objectKey("", obj.Object), cluster, "", "", true, expandAll)
+	}
+	return ret
+}
+
+func (p *InteractivePrinter) flattenObject(lines []treeLine, obj status.ObjectStatus, parent *status.Object,
+	key, cluster, prefixHead, prefix string, root, expandAll bool) []treeLine {
+	subObjects := p.filterVisible(obj.SubStatuses)
+	expanded, set := p.expanded[key]
+	if expandAll {
+		expanded = true
+	} else if !set {
+		// Same default as the TreePrinter: show details for abnormal objects only.
+		expanded = p.PrintOpts.ShowOk || obj.Status().Result > status.Ok || obj.Status().Progressing
+	}
+
+	lines = append(lines, treeLine{
+		key:         key,
+		obj:         obj,
+		parent:      parent,
+		cluster:     cluster,
+		prefix:      prefixHead,
+		root:        root,
+		hasChildren: len(subObjects) > 0,
+		expanded:    expanded,
+	})
+	if !expanded {
+		return lines
+	}
+
+	sortObjects(subObjects)
+	for j, sub := range subObjects {
+		head, tail := `├─ `, `│  `
+		if j == len(subObjects)-1 {
+			head, tail = `└─ `, "   "
+		}
+		lines = p.flattenObject(lines, sub, obj.Object, objectKey(key, sub.Object), cluster,
+			prefix+head, prefix+tail, false, expandAll)
+	}
+	return lines
+}
+
+func (p *InteractivePrinter) filterVisible(objects []status.ObjectStatus) []status.ObjectStatus {
+	var ret []status.ObjectStatus
+	for _, obj := range objects {
+		if p.visible(obj) {
+			ret = append(ret, obj)
+		}
+	}
+	return ret
+}
+
+// objectKey identifies the object in the tree across updates.
+func objectKey(parentKey string, obj *status.Object) string {
+	return fmt.Sprintf("%s|%s/%s/%s", parentKey, obj.Kind, obj.GetNamespace(), obj.GetName())
+}
+
+// details returns the lines with the full conditions of the object.
+func (p *InteractivePrinter) details(line treeLine) []string {
+	opts := p.PrintOpts
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "%s\n", formatObject(opts, line.obj, true, opts.ShowGroup))
+	if line.obj.ObjStatus.Provenance != nil {
+		fmt.Fprintf(sb, "  why: %s\n", line.obj.ObjStatus.Provenance)
+	}
+	if line.obj.ObjStatus.Err != nil {
+		fmt.Fprintf(sb, "  error: %s\n", line.obj.ObjStatus.Err)
+	}
+
+	for _, cond := range line.obj.Conditions {
+		fmt.Fprintf(sb, "\n%s  %s  %s\n", formatConditionType(opts, cond),
+			formatConditionAge(opts, cond), formatConditionReason(opts, cond))
+		if cond.Message != "" {
+			for _, l := range strings.Split(strings.TrimRight(cond.Message, "\n"), "\n") {
+				fmt.Fprintf(sb, "    %s\n", l)
+			}
+		}
+		if cond.CondStatus != nil && cond.CondStatus.Provenance != nil {
+			fmt.Fprintf(sb, "    why: %s\n", cond.CondStatus.Provenance)
+		}
+	}
+	return p.wrap(sb.String())
+}
+
+// wrap splits the text into lines fitting the terminal width.
+func (p *InteractivePrinter) wrap(text string) []string {
+	width, _ := p.size()
+	text = strings.TrimRight(text, "\n")
+	if text == "" {
+		return []string{""}
+	}
+	var ret []string
+	for _, l := range strings.Split(sanitizeTerminal(text), "\n") {
+		if width <= 0 {
+			ret = append(ret, l)
+			continue
+		}
+		if controlRe.MatchString(l) {
+			if utf8.RuneCountInString(controlRe.ReplaceAllString(l, "")) <= width {
+				ret = append(ret, l)
+				continue
+			}
+			// The colors can't be kept when wrapping the line.
+			l = controlRe.ReplaceAllString(l, "")
+		}
+		ret = append(ret, strings.Split(strings.TrimRight(wrapLines(l, width, 0, "  "), "\n"), "\n")...)
+	}
+	return ret
+}
+
+func (p *InteractivePrinter) render() {
+	width, height := p.size()
+	bodyHeight := max(height-2, 1)
+
+	var body []string
+	var footer string
+	switch p.view {
+	case treeView:
+		body, footer = p.renderTree(bodyHeight)
+	default:
+		p.viewOffset = max(min(p.viewOffset, len(p.viewLines)-bodyHeight), 0)
+		body = p.viewLines[p.viewOffset:min(p.viewOffset+bodyHeight, len(p.viewLines))]
+		footer = "↑/↓ scroll  pgup/pgdown page  esc back"
+	}
+
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "%c[H", ESC)
+	writeLine := func(s string) {
+		fmt.Fprintf(sb, "%s%c[%dm%c[K\r\n", truncateKeepControl(sanitizeTerminal(s), width), ESC, RESET, ESC)
+	}
+	writeLine(p.header())
+	for i := 0; i < bodyHeight; i++ {
+		if i < len(body) {
+			writeLine(body[i])
+		} else {
+			writeLine("")
+		}
+	}
+	if p.statusMsg != "" {
+		footer = p.statusMsg
+	}
+	fmt.Fprintf(sb, "%s%c[K%c[J", truncateKeepControl(sanitizeTerminal(footer), width), ESC, ESC)
+	fmt.Fprint(p.out, sb.String())
+}
+
+func (p *InteractivePrinter) renderTree(height int) ([]string, string) {
+	footer := "↑/↓ move  ←/→ collapse/expand  enter details  l logs  n/N next/prev error  f filter  q quit"
+	if len(p.lines) == 0 {
+		if p.statuses == nil && p.err == nil {
+			return []string{"Loading..."}, footer
+		}
+		return []string{"No objects to show"}, footer
+	}
+
+	// Keep the cursor on the screen.
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	}
+	if p.cursor >= p.offset+height {
+		p.offset = p.cursor - height + 1
+	}
+	p.offset = max(min(p.offset, len(p.lines)-height), 0)
+
+	var ret []string
+	for i := p.offset; i < min(p.offset+height, len(p.lines)); i++ {
+		l := p.lines[i]
+		marker := "  "
+		if l.hasChildren {
+			if l.expanded {
+				marker = "▾ "
+			} else {
+				marker = "▸ "
+			}
+		}
+		cursor := "  "
+		if i == p.cursor {
+			cursor = "> "
+		}
+		ret = append(ret, cursor+l.prefix+marker+formatObject(p.PrintOpts, l.obj, l.root, p.PrintOpts.ShowGroup))
+	}
+	return ret, footer
+}
+
+func (p *InteractivePrinter) header() string {
+	var parts []string
+	switch p.view {
+	case detailsView:
+		parts = append(parts, "details")
+	case logsView:
+		parts = append(parts, "logs")
+	default:
+		filter := "all"
+		if p.minResult > status.Ok {
+			filter = p.minResult.String() + "+"
+		}
+		parts = append(parts, "filter: "+filter)
+	}
+	if !p.updated.IsZero() {
+		parts = append(parts, "updated: "+p.updated.Format(time.TimeOnly))
+	}
+	if p.finished {
+		parts = append(parts, "polling finished")
+	}
+	if p.err != nil {
+		parts = append(parts, "error: "+p.err.Error())
+	}
+	return strings.Join(parts, "  ")
+}
+
+// sanitizeTerminal removes the control characters and escape sequences
+// from the text coming from the cluster (pod logs, condition messages),
+// so that they can't change the state of the terminal. Only the colors
+// (SGR sequences) are kept, the tabs are expanded to spaces.
+func sanitizeTerminal(s string) string {
+	s = terminalControlRe.ReplaceAllStringFunc(s, func(seq string) string {
+		if controlRe.FindString(seq) == seq {
+			return seq
+		}
+		return ""
+	})
+	return strings.ReplaceAll(s, "\t", "    ")
+}
+
+// truncateKeepControl cuts the string to the width, not counting the
+// control sequences.
+func truncateKeepControl(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+	return strings.TrimRight(padStringKeepControl(s, width), " ")
+}
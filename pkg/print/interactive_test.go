@@ -0,0 +1,566 @@
+package print
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inecas/kube-health/internal/test"
+	"github.com/inecas/kube-health/pkg/eval"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+// newTestInteractivePrinter creates a printer for a terminal of the size
+// showing the statuses.
+func newTestInteractivePrinter(statuses []status.ObjectStatus, width, height int,
+	logFn LogFn) (*InteractivePrinter, *strings.Builder) {
+	out := &strings.Builder{}
+	p := NewInteractivePrinter(PrintOptions{}, strings.NewReader(""), out,
+		func() (int, int) { return width, height }, nil, logFn, eval.PodLogOptions{TailLines: 10}, nil)
+	p.update(eval.StatusUpdate{Statuses: statuses})
+	// Keep the header stable.
+	p.updated = time.Time{}
+	return p, out
+}
+
+// screen renders the printer and returns the lines shown on the terminal,
+// without the escape sequences.
+func screen(p *InteractivePrinter, out *strings.Builder) string {
+	out.Reset()
+	p.render()
+	var lines []string
+	for _, l := range strings.Split(terminalControlRe.ReplaceAllString(out.String(), ""), "\n") {
+		lines = append(lines, strings.TrimRight(l, " "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestSanitizeTerminal(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		expected string
+	}{
+		{"plain", "hello\tworld\n", "hello    world\n"},
+		{"colors kept", "\x1b[31mred\x1b[0m", "\x1b[31mred\x1b[0m"},
+		{"csi", "\x1b[2J\x1b[1;1Hcleared\x1b[?1049l", "cleared"},
+		{"osc title", "\x1b]0;pwned\x07title", "title"},
+		{"osc st", "\x1b]8;;http://example.com\x1b\\link", "link"},
+		{"carriage return", "progress\r100%", "progress100%"},
+		{"c0 and c1", "bell\x07 del\x7f c1\u009b", "bell del c1"},
+		{"lone escape", "end\x1b", "end"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, sanitizeTerminal(tt.in))
+		})
+	}
+}
+
+func TestInteractivePrinterSanitize(t *testing.T) {
+	pod := testStatus("Pod", "web", status.Error, testStatus("Container", "app", status.Error))
+	pod.Conditions = []status.ConditionStatus{
+		testCondition("Ready", "Failed", "\x1b]0;pwned\x07bad\x1b[2J message", status.Error),
+	}
+	logFn := func(ctx context.Context, cluster string, pod *status.Object, container string,
+		opts eval.PodLogOptions) (string, error) {
+		return "\x1b[31mcolored\x1b[0m\r\n\x1b[1;1Hmoved\n", nil
+	}
+	p, out := newTestInteractivePrinter([]status.ObjectStatus{pod}, 60, 8, logFn)
+
+	p.handleKey(t.Context(), keyEnter)
+	s := screen(p, out)
+	assert.NotContains(t, out.String(), "\x1b]")
+	assert.NotContains(t, out.String(), "\x1b[2J")
+	test.AssertStr(t, `
+details
+Error default/Pod/web
+
+(Error) Ready=False    Failed
+    bad message
+
+
+↑/↓ scroll  pgup/pgdown page  esc back
+`, s)
+
+	p.handleKey(t.Context(), keyEsc)
+	p.handleKey(t.Context(), "l")
+	p.updateLogs(<-p.logsChan)
+	s = screen(p, out)
+	assert.Contains(t, out.String(), "\x1b[31mcolored")
+	assert.NotContains(t, out.String(), "\x1b[1;1H")
+	test.AssertStr(t, `
+logs
+colored
+moved
+
+
+
+
+↑/↓ scroll  pgup/pgdown page  esc back
+`, s)
+}
+
+// keyReader returns one key sequence per read, as the terminal does.
+type keyReader struct {
+	seqs []string
+}
+
+func (r *keyReader) Read(b []byte) (int, error) {
+	if len(r.seqs) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(b, r.seqs[0])
+	r.seqs = r.seqs[1:]
+	return n, nil
+}
+
+// press feeds the key sequences through the input reader and handles the
+// keys. It returns false when the UI quits.
+func press(t *testing.T, p *InteractivePrinter, seqs ...string) bool {
+	var keys []key
+	for k := range readKeys(&keyReader{seqs: seqs}) {
+		keys = append(keys, k)
+	}
+	for _, k := range keys {
+		if !p.handleKey(t.Context(), k) {
+			return false
+		}
+	}
+	return true
+}
+
+func testInteractiveStatuses() []status.ObjectStatus {
+	return []status.ObjectStatus{
+		testStatus("Deployment", "web", status.Error,
+			testStatus("ReplicaSet", "web-1", status.Error,
+				testStatus("Pod", "web-1-a", status.Error),
+				testStatus("Pod", "web-1-b", status.Ok))),
+		// The error is hidden in the collapsed sub-tree.
+		testStatus("Deployment", "api", status.Ok,
+			testStatus("ReplicaSet", "api-1", status.Ok,
+				testStatus("Pod", "api-1-a", status.Error))),
+		testStatus("Service", "db", status.Warning),
+		testStatus("ConfigMap", "cache", status.Unknown),
+	}
+}
+
+func TestInteractivePrinterKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		keys     []string
+		expected string
+	}{
+		{
+			name: "initial",
+			keys: []string{},
+			expected: `
+filter: all
+> ▾ Error default/Deployment/web
+  └─ ▾ Error ReplicaSet/web-1
+     ├─   Error Pod/web-1-a
+     └─   Ok Pod/web-1-b
+    Warning default/Service/db
+    Unknown default/ConfigMap/cache
+  ▸ Ok default/Deployment/api
+
+
+
+↑/↓ move  ←/→ collapse/expand  enter details  l logs  n/N next/prev error  f filter  q quit
+`,
+		},
+		{
+			name: "down and up",
+			keys: []string{"j", "\x1b[B", "\x1b[A"},
+			expected: `
+filter: all
+  ▾ Error default/Deployment/web
+> └─ ▾ Error ReplicaSet/web-1
+     ├─   Error Pod/web-1-a
+     └─   Ok Pod/web-1-b
+    Warning default/Service/db
+    Unknown default/ConfigMap/cache
+  ▸ Ok default/Deployment/api
+
+
+
+↑/↓ move  ←/→ collapse/expand  enter details  l logs  n/N next/prev error  f filter  q quit
+`,
+		},
+		{
+			name: "collapse",
+			keys: []string{"\x1b[D"},
+			expected: `
+filter: all
+> ▸ Error default/Deployment/web
+    Warning default/Service/db
+    Unknown default/ConfigMap/cache
+  ▸ Ok default/Deployment/api
+
+
+
+
+
+
+↑/↓ move  ←/→ collapse/expand  enter details  l logs  n/N next/prev error  f filter  q quit
+`,
+		},
+		{
+			name: "expand",
+			keys: []string{"G", "\x1b[C"},
+			expected: `
+filter: all
+  ▾ Error default/Deployment/web
+  └─ ▾ Error ReplicaSet/web-1
+     ├─   Error Pod/web-1-a
+     └─   Ok Pod/web-1-b
+    Warning default/Service/db
+    Unknown default/ConfigMap/cache
+> ▾ Ok default/Deployment/api
+  └─ ▸ Ok ReplicaSet/api-1
+
+
+↑/↓ move  ←/→ collapse/expand  enter details  l logs  n/N next/prev error  f filter  q quit
+`,
+		},
+		{
+			name: "toggle",
+			keys: []string{"G", " ", " "},
+			expected: `
+filter: all
+  ▾ Error default/Deployment/web
+  └─ ▾ Error ReplicaSet/web-1
+     ├─   Error Pod/web-1-a
+     └─   Ok Pod/web-1-b
+    Warning default/Service/db
+    Unknown default/ConfigMap/cache
+> ▸ Ok default/Deployment/api
+
+
+
+↑/↓ move  ←/→ collapse/expand  enter details  l logs  n/N next/prev error  f filter  q quit
+`,
+		},
+		{
+			name: "page down and home",
+			keys: []string{"\x1b[6~"},
+			expected: `
+filter: all
+  ▾ Error default/Deployment/web
+  └─ ▾ Error ReplicaSet/web-1
+     ├─   Error Pod/web-1-a
+     └─   Ok Pod/web-1-b
+    Warning default/Service/db
+    Unknown default/ConfigMap/cache
+> ▸ Ok default/Deployment/api
+
+
+
+↑/↓ move  ←/→ collapse/expand  enter details  l logs  n/N next/prev error  f filter  q quit
+`,
+		},
+		{
+			name: "end and home",
+			keys: []string{"G", "g"},
+			expected: `
+filter: all
+> ▾ Error default/Deployment/web
+  └─ ▾ Error ReplicaSet/web-1
+     ├─   Error Pod/web-1-a
+     └─   Ok Pod/web-1-b
+    Warning default/Service/db
+    Unknown default/ConfigMap/cache
+  ▸ Ok default/Deployment/api
+
+
+
+↑/↓ move  ←/→ collapse/expand  enter details  l logs  n/N next/prev error  f filter  q quit
+`,
+		},
+		{
+			name: "filter warning",
+			keys: []string{"f"},
+			expected: `
+filter: Warning+
+> ▾ Error default/Deployment/web
+  └─ ▾ Error ReplicaSet/web-1
+     └─   Error Pod/web-1-a
+    Warning default/Service/db
+    Unknown default/ConfigMap/cache
+  ▸ Ok default/Deployment/api
+
+
+
+
+↑/↓ move  ←/→ collapse/expand  enter details  l logs  n/N next/prev error  f filter  q quit
+`,
+		},
+		{
+			name: "filter error",
+			keys: []string{"f", "f"},
+			expected: `
+filter: Error+
+> ▾ Error default/Deployment/web
+  └─ ▾ Error ReplicaSet/web-1
+     └─   Error Pod/web-1-a
+    Unknown default/ConfigMap/cache
+  ▸ Ok default/Deployment/api
+
+
+
+
+
+↑/↓ move  ←/→ collapse/expand  enter details  l logs  n/N next/prev error  f filter  q quit
+`,
+		},
+		{
+			name: "filter all",
+			keys: []string{"f", "f", "f"},
+			expected: `
+filter: all
+> ▾ Error default/Deployment/web
+  └─ ▾ Error ReplicaSet/web-1
+     ├─   Error Pod/web-1-a
+     └─   Ok Pod/web-1-b
+    Warning default/Service/db
+    Unknown default/ConfigMap/cache
+  ▸ Ok default/Deployment/api
+
+
+
+↑/↓ move  ←/→ collapse/expand  enter details  l logs  n/N next/prev error  f filter  q quit
+`,
+		},
+		{
+			name: "next error",
+			keys: []string{"n", "n"},
+			expected: `
+filter: all
+  ▾ Error default/Deployment/web
+  └─ ▾ Error ReplicaSet/web-1
+>    ├─   Error Pod/web-1-a
+     └─   Ok Pod/web-1-b
+    Warning default/Service/db
+    Unknown default/ConfigMap/cache
+  ▸ Ok default/Deployment/api
+
+
+
+↑/↓ move  ←/→ collapse/expand  enter details  l logs  n/N next/prev error  f filter  q quit
+`,
+		},
+		{
+			name: "next error expands",
+			keys: []string{"n", "n", "n"},
+			expected: `
+filter: all
+  ▾ Error default/Deployment/web
+  └─ ▾ Error ReplicaSet/web-1
+     ├─   Error Pod/web-1-a
+     └─   Ok Pod/web-1-b
+    Warning default/Service/db
+    Unknown default/ConfigMap/cache
+  ▾ Ok default/Deployment/api
+  └─ ▾ Ok ReplicaSet/api-1
+>    └─   Error Pod/api-1-a
+
+↑/↓ move  ←/→ collapse/expand  enter details  l logs  n/N next/prev error  f filter  q quit
+`,
+		},
+		{
+			name: "previous error wraps",
+			keys: []string{"N"},
+			expected: `
+filter: all
+  ▾ Error default/Deployment/web
+  └─ ▾ Error ReplicaSet/web-1
+     ├─   Error Pod/web-1-a
+     └─   Ok Pod/web-1-b
+    Warning default/Service/db
+    Unknown default/ConfigMap/cache
+  ▾ Ok default/Deployment/api
+  └─ ▾ Ok ReplicaSet/api-1
+>    └─   Error Pod/api-1-a
+
+↑/↓ move  ←/→ collapse/expand  enter details  l logs  n/N next/prev error  f filter  q quit
+`,
+		},
+		{
+			name: "details",
+			keys: []string{"\r"},
+			expected: `
+details
+Error default/Deployment/web
+
+
+
+
+
+
+
+
+
+↑/↓ scroll  pgup/pgdown page  esc back
+`,
+		},
+		{
+			name: "details back",
+			keys: []string{"\r", "\x1b"},
+			expected: `
+filter: all
+> ▾ Error default/Deployment/web
+  └─ ▾ Error ReplicaSet/web-1
+     ├─   Error Pod/web-1-a
+     └─   Ok Pod/web-1-b
+    Warning default/Service/db
+    Unknown default/ConfigMap/cache
+  ▸ Ok default/Deployment/api
+
+
+
+↑/↓ move  ←/→ collapse/expand  enter details  l logs  n/N next/prev error  f filter  q quit
+`,
+		},
+		{
+			name: "logs unavailable",
+			keys: []string{"l"},
+			expected: `
+filter: all
+> ▾ Error default/Deployment/web
+  └─ ▾ Error ReplicaSet/web-1
+     ├─   Error Pod/web-1-a
+     └─   Ok Pod/web-1-b
+    Warning default/Service/db
+    Unknown default/ConfigMap/cache
+  ▸ Ok default/Deployment/api
+
+
+
+Logs are available only for pods and containers
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, out := newTestInteractivePrinter(testInteractiveStatuses(), 100, 12, nil)
+			assert.True(t, press(t, p, tt.keys...))
+			test.AssertStr(t, tt.expected, screen(p, out))
+		})
+	}
+}
+
+func TestInteractivePrinterQuit(t *testing.T) {
+	p, _ := newTestInteractivePrinter(testInteractiveStatuses(), 100, 12, nil)
+	assert.False(t, press(t, p, "j", "q"))
+	assert.False(t, press(t, p, "\x03"))
+	// Quitting the details view gets back to the tree.
+	assert.True(t, press(t, p, "\r", "q"))
+	assert.Equal(t, treeView, p.view)
+}
+
+func TestInteractivePrinterScroll(t *testing.T) {
+	p, out := newTestInteractivePrinter(testInteractiveStatuses(), 100, 6, nil)
+	assert.True(t, press(t, p, "G"))
+	test.AssertStr(t, `
+filter: all
+     └─   Ok Pod/web-1-b
+    Warning default/Service/db
+    Unknown default/ConfigMap/cache
+> ▸ Ok default/Deployment/api
+↑/↓ move  ←/→ collapse/expand  enter details  l logs  n/N next/prev error  f filter  q quit
+`, screen(p, out))
+
+	// The lines are cut to the new size of the terminal.
+	p.size = func() (int, int) { return 30, 4 }
+	assert.True(t, press(t, p, "k"))
+	test.AssertStr(t, `
+filter: all
+    Warning default/Service/db
+>   Unknown default/ConfigMap/
+↑/↓ move  ←/→ collapse/expand
+`, screen(p, out))
+}
+
+func TestInteractivePrinterNoErrors(t *testing.T) {
+	p, out := newTestInteractivePrinter([]status.ObjectStatus{testStatus("Service", "db", status.Ok)}, 60, 4, nil)
+	assert.True(t, press(t, p, "n"))
+	test.AssertStr(t, `
+filter: all
+>   Ok default/Service/db
+
+No errors found
+`, screen(p, out))
+
+	p.update(eval.StatusUpdate{Statuses: []status.ObjectStatus{}})
+	p.updated = time.Time{}
+	assert.True(t, press(t, p, "j"))
+	test.AssertStr(t, `
+filter: all
+No objects to show
+
+↑/↓ move  ←/→ collapse/expand  enter details  l logs  n/N ne
+`, screen(p, out))
+}
+
+func TestInteractivePrinterWrapDetails(t *testing.T) {
+	pod := testStatus("Pod", "web", status.Error)
+	pod.Conditions = []status.ConditionStatus{
+		testCondition("Ready", "Failed", "containers with unready status: [app sidecar]", status.Error),
+	}
+	p, out := newTestInteractivePrinter([]status.ObjectStatus{pod}, 30, 8, nil)
+	assert.True(t, press(t, p, "\r"))
+	test.AssertStr(t, `
+details
+Error default/Pod/web
+
+(Error) Ready=False    Failed
+    containers with unready
+  status: [app sidecar]
+
+↑/↓ scroll  pgup/pgdown page
+`, screen(p, out))
+}
+
+func TestInteractivePrinterLogOptions(t *testing.T) {
+	pod := testStatus("Pod", "web", status.Error,
+		testStatus("Container", "app", status.Error),
+		testStatus("Container", "sidecar", status.Ok))
+	pod.Object.Unstructured.Object["status"] = map[string]interface{}{
+		"containerStatuses": []interface{}{
+			map[string]interface{}{
+				"name":      "app",
+				"lastState": map[string]interface{}{"terminated": map[string]interface{}{"exitCode": int64(1)}},
+			},
+			map[string]interface{}{"name": "sidecar"},
+		},
+	}
+
+	var requested []eval.PodLogOptions
+	logFn := func(ctx context.Context, cluster string, pod *status.Object, container string,
+		opts eval.PodLogOptions) (string, error) {
+		requested = append(requested, opts)
+		return container + " logs\n", nil
+	}
+	p, out := newTestInteractivePrinter([]status.ObjectStatus{pod}, 60, 8, logFn)
+	p.logOpts = eval.PodLogOptions{TailLines: 20, Previous: true}
+
+	assert.True(t, press(t, p, "l"))
+	p.updateLogs(<-p.logsChan)
+	// The previous logs are loaded only for the restarted container.
+	assert.Equal(t, []eval.PodLogOptions{{TailLines: 20, Previous: true}, {TailLines: 20}}, requested)
+	test.AssertStr(t, `
+logs
+==> app (previous) <==
+app logs
+==> sidecar <==
+sidecar logs
+
+
+↑/↓ scroll  pgup/pgdown page  esc back
+`, screen(p, out))
+}
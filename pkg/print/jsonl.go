@@ -0,0 +1,45 @@
+package print
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/inecas/kube-health/pkg/api/v1alpha1"
+	"github.com/inecas/kube-health/pkg/eval"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+// JSONLinesPrinter prints every update as a compact HealthReport document
+// on a single line (JSON Lines), numbered by the Sequence field. It's meant
+// to be used with NewStreamingPrinter, so that the updates can be consumed
+// by jq or log shippers. Failed evaluations are reported as documents with
+// the Error field set, so that the consumers notice the gaps.
+type JSONLinesPrinter struct {
+	// Cluster identifies the evaluated cluster in the reports. Optional.
+	Cluster *v1alpha1.ClusterInfo
+	// Info adds the wait details to the reports. Optional.
+	Info *RunInfo
+
+	sequence int64
+}
+
+func NewJSONLinesPrinter(cluster *v1alpha1.ClusterInfo) *JSONLinesPrinter {
+	return &JSONLinesPrinter{Cluster: cluster}
+}
+
+func (p *JSONLinesPrinter) PrintStatuses(statuses []status.ObjectStatus, w io.Writer) {
+	p.PrintUpdate(eval.StatusUpdate{Statuses: statuses}, w)
+}
+
+func (p *JSONLinesPrinter) PrintUpdate(update eval.StatusUpdate, w io.Writer) {
+	p.sequence++
+	report := newHealthReport(update, p.Cluster, p.Info)
+	report.Sequence = p.sequence
+	// The control characters (e.g. in the logs) are escaped by the encoder.
+	data, err := json.Marshal(report)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Fprintf(w, "%s\n", data)
+}
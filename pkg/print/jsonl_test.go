@@ -0,0 +1,58 @@
+package print
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inecas/kube-health/pkg/api/v1alpha1"
+	"github.com/inecas/kube-health/pkg/eval"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+func TestJSONLinesPrinter(t *testing.T) {
+	p := NewJSONLinesPrinter(&v1alpha1.ClusterInfo{Context: "prod"})
+	sb := &strings.Builder{}
+
+	p.PrintUpdate(eval.StatusUpdate{Statuses: []status.ObjectStatus{testDeployment()}}, sb)
+	p.PrintUpdate(eval.StatusUpdate{Error: errors.New("connection refused")}, sb)
+	p.PrintStatuses([]status.ObjectStatus{testStatus("Service", "db", status.Ok)}, sb)
+
+	lines := strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n")
+	require.Len(t, lines, 3)
+
+	var reports []v1alpha1.HealthReport
+	for _, line := range lines {
+		var report v1alpha1.HealthReport
+		require.NoError(t, json.Unmarshal([]byte(line), &report))
+		reports = append(reports, report)
+	}
+
+	assert.Equal(t, []int64{1, 2, 3}, []int64{reports[0].Sequence, reports[1].Sequence, reports[2].Sequence})
+	assert.Equal(t, "prod", reports[0].Cluster.Context)
+	assert.Equal(t, 4, reports[0].Summary.Objects)
+	assert.Empty(t, reports[0].Error)
+
+	// The failed evaluation is reported in order with the rest.
+	assert.Equal(t, "connection refused", reports[1].Error)
+	assert.Empty(t, reports[1].Objects)
+
+	assert.Equal(t, "db", reports[2].Objects[0].Object.Name)
+}
+
+func TestJSONLinesPrinterEscaping(t *testing.T) {
+	pod := testStatus("Pod", "web", status.Error)
+	pod.Conditions = []status.ConditionStatus{
+		testCondition("Ready", "CrashLoopBackOff", "\x1b[31mpanic\x1b[0m\nline\ttwo", status.Error),
+	}
+	sb := &strings.Builder{}
+	NewJSONLinesPrinter(nil).PrintStatuses([]status.ObjectStatus{pod}, sb)
+
+	assert.Equal(t, 1, strings.Count(sb.String(), "\n"))
+	assert.NotContains(t, sb.String(), "\x1b")
+	assert.Contains(t, sb.String(), `"message":"\u001b[31mpanic\u001b[0m\nline\ttwo"`)
+}
@@ -0,0 +1,140 @@
+package print
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Content string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// JUnitPrinter prints the statuses as a JUnit XML report. Every root object
+// is a test suite and every condition of the object and its sub-objects is
+// a test case, failing when the condition is unhealthy. Objects without
+// conditions are represented by a single test case.
+type JUnitPrinter struct{}
+
+func NewJUnitPrinter() *JUnitPrinter {
+	return &JUnitPrinter{}
+}
+
+func (p *JUnitPrinter) PrintStatuses(statuses []status.ObjectStatus, w io.Writer) {
+	report := junitTestSuites{Name: "kube-health"}
+	timestamp := time.Now().Format(time.RFC3339)
+
+	sortObjects(statuses)
+	for _, root := range statuses {
+		if root.Object == nil {
+			continue
+		}
+		suite := junitTestSuite{
+			Name:      objectName(root.Object),
+			Time:      "0",
+			Timestamp: timestamp,
+		}
+		walkTree(root, func(obj status.ObjectStatus, path []status.ObjectStatus) {
+			suite.Cases = append(suite.Cases, junitCases(suite.Name, obj, path)...)
+		})
+		for _, c := range suite.Cases {
+			suite.Tests++
+			if c.Failure != nil {
+				suite.Failures++
+			}
+			if c.Skipped != nil {
+				suite.Skipped++
+			}
+		}
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Skipped += suite.Skipped
+		report.Suites = append(report.Suites, suite)
+	}
+
+	out, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Fprintf(w, "%s%s\n", xml.Header, out)
+}
+
+func junitCases(suite string, obj status.ObjectStatus, path []status.ObjectStatus) []junitTestCase {
+	name := relativeName(obj.Object, path)
+	if len(path) == 0 {
+		name = fmt.Sprintf("%s/%s", obj.Object.Kind, obj.Object.GetName())
+	}
+
+	if len(obj.Conditions) == 0 {
+		tc := junitTestCase{Name: name, Classname: suite, Time: "0"}
+		s := obj.Status()
+		switch {
+		case obj.ObjStatus.Err != nil:
+			tc.Failure = &junitFailure{Type: s.Result.String(), Message: obj.ObjStatus.Err.Error()}
+		case unhealthy(s):
+			tc.Failure = &junitFailure{Type: s.Result.String(), Message: s.Status}
+		case s.Result == status.Unknown:
+			tc.Skipped = &junitSkipped{Message: "Unknown"}
+		}
+		return []junitTestCase{tc}
+	}
+
+	var ret []junitTestCase
+	for _, cond := range obj.Conditions {
+		tc := junitTestCase{
+			Name:      fmt.Sprintf("%s %s", name, cond.Type),
+			Classname: suite,
+			Time:      "0",
+		}
+		if s := cond.Status(); unhealthy(s) {
+			message := fmt.Sprintf("%s=%s", cond.Type, cond.Condition.Status)
+			if cond.Reason != "" {
+				message += ": " + cond.Reason
+			}
+			tc.Failure = &junitFailure{
+				Type:    s.Result.String(),
+				Message: message,
+				Content: strings.TrimSpace(cond.Message),
+			}
+		}
+		ret = append(ret, tc)
+	}
+	return ret
+}
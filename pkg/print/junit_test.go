@@ -0,0 +1,61 @@
+package print
+
+import (
+	"encoding/xml"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inecas/kube-health/internal/test"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+var junitTimestampRe = regexp.MustCompile(`timestamp="[^"]*"`)
+
+func TestJUnitPrinter(t *testing.T) {
+	unknown := testStatus("Service", "db", status.Unknown)
+	failed := testStatus("ConfigMap", "config", status.Unknown)
+	failed.ObjStatus.Err = errors.New("forbidden: <no access>")
+
+	sb := &strings.Builder{}
+	NewJUnitPrinter().PrintStatuses([]status.ObjectStatus{unknown, testDeployment(), failed}, sb)
+	out := junitTimestampRe.ReplaceAllString(sb.String(), `timestamp="T"`)
+	test.AssertStr(t, `
+<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="kube-health" tests="7" failures="4" skipped="1">
+  <testsuite name="default/ConfigMap/config" tests="1" failures="1" skipped="0" time="0" timestamp="T">
+    <testcase name="ConfigMap/config" classname="default/ConfigMap/config" time="0">
+      <failure message="forbidden: &lt;no access&gt;" type="Unknown"></failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="default/Deployment/web" tests="5" failures="3" skipped="0" time="0" timestamp="T">
+    <testcase name="Deployment/web" classname="default/Deployment/web" time="0">
+      <failure message="Error" type="Error"></failure>
+    </testcase>
+    <testcase name="ReplicaSet/web-1" classname="default/Deployment/web" time="0">
+      <failure message="Error" type="Error"></failure>
+    </testcase>
+    <testcase name="ReplicaSet/web-1 &gt; Pod/web-1-a Ready" classname="default/Deployment/web" time="0"></testcase>
+    <testcase name="ReplicaSet/web-1 &gt; Pod/web-1-b Ready" classname="default/Deployment/web" time="0">
+      <failure message="Ready=False: ContainersNotReady" type="Error">containers with unready status: [web] &lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; &amp; more&#xA;see | logs</failure>
+    </testcase>
+    <testcase name="ReplicaSet/web-1 &gt; Pod/web-1-b PodScheduled" classname="default/Deployment/web" time="0"></testcase>
+  </testsuite>
+  <testsuite name="default/Service/db" tests="1" failures="0" skipped="1" time="0" timestamp="T">
+    <testcase name="Service/db" classname="default/Service/db" time="0">
+      <skipped message="Unknown"></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+	`, out)
+
+	// The escaped message is read back unchanged.
+	var report junitTestSuites
+	require.NoError(t, xml.Unmarshal([]byte(sb.String()), &report))
+	assert.Equal(t, "containers with unready status: [web] <script>alert(\"x\")</script> & more\nsee | logs",
+		report.Suites[1].Cases[3].Failure.Content)
+}
@@ -1,99 +1,74 @@
 package print
 
 import (
+	"fmt"
 	"io"
+	"os"
+	"time"
 
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/printers"
 
+	"github.com/inecas/kube-health/pkg/api/v1alpha1"
+	"github.com/inecas/kube-health/pkg/eval"
 	"github.com/inecas/kube-health/pkg/status"
 )
 
 // Genric printer as a wrapper around kubectl standard printers, to produce
 // json, yaml and other standard printing capabilities.
-
+//
+// The statuses are converted to the versioned v1alpha1.HealthReport document
+// before printing.
 type KubectlPrinter struct {
 	Printer printers.ResourcePrinter
+	// Cluster identifies the evaluated cluster in the report. Optional.
+	Cluster *v1alpha1.ClusterInfo
+	// Info adds the wait details to the report. Optional.
+	Info *RunInfo
+	// ErrOut receives the printing failures, e.g. an invalid jsonpath
+	// template. If not set, os.Stderr is used.
+	ErrOut io.Writer
+
+	err error
 }
 
-type objectWrapper struct {
-	Object     corev1.ObjectReference   `json:"object"`
-	Status     status.Status            `json:"health"`
-	Conditions []status.ConditionStatus `json:"conditions,omitempty"`
-	Subobjects []*objectWrapper         `json:"subobjects,omitempty"`
+func NewKubectlPrinter(printer printers.ResourcePrinter, cluster *v1alpha1.ClusterInfo) *KubectlPrinter {
+	return &KubectlPrinter{Printer: printer, Cluster: cluster}
 }
 
-// objectWrapper implements runtime.Object interface
-var _ runtime.Object = &objectWrapper{}
-
-func (ow *objectWrapper) GetObjectKind() schema.ObjectKind {
-	return schema.EmptyObjectKind
-
+// Err returns the first printing failure, so that the caller can report
+// the run as failed.
+func (p *KubectlPrinter) Err() error {
+	return p.err
 }
 
-func (ow *objectWrapper) DeepCopyObject() runtime.Object {
-	return ow.DeepCopy()
+func (p *KubectlPrinter) PrintStatuses(statuses []status.ObjectStatus, w io.Writer) {
+	p.PrintUpdate(eval.StatusUpdate{Statuses: statuses}, w)
 }
 
-func (ow *objectWrapper) DeepCopy() *objectWrapper {
-	var conditions []status.ConditionStatus
-	for _, c := range ow.Conditions {
-		conditions = append(conditions, *c.DeepCopy())
-	}
-
-	var subobjects []*objectWrapper
-	for _, o := range ow.Subobjects {
-		subobjects = append(subobjects, o.DeepCopy())
+func (p *KubectlPrinter) PrintUpdate(update eval.StatusUpdate, w io.Writer) {
+	report := newHealthReport(update, p.Cluster, p.Info)
+	err := p.Printer.PrintObj(report, w)
+	if err == nil || p.err != nil {
+		// The repeated failures would only flood the output.
+		return
 	}
-
-	return &objectWrapper{
-		Object:     *ow.Object.DeepCopy(),
-		Status:     *ow.Status.DeepCopy(),
-		Conditions: conditions,
-		Subobjects: subobjects,
+	p.err = err
+	errOut := p.ErrOut
+	if errOut == nil {
+		errOut = os.Stderr
 	}
+	fmt.Fprintf(errOut, "Error: can't print the report: %s\n", err)
 }
 
-func wrapObjectStatus(s status.ObjectStatus) *objectWrapper {
-	ret := objectWrapper{
-		Object: corev1.ObjectReference{
-			APIVersion: s.Object.APIVersion,
-			Kind:       s.Object.Kind,
-			Name:       s.Object.Name,
-			Namespace:  s.Object.Namespace,
-			UID:        s.Object.UID,
-		},
-		Status:     s.ObjStatus,
-		Conditions: s.Conditions,
+func newHealthReport(update eval.StatusUpdate, cluster *v1alpha1.ClusterInfo, info *RunInfo) *v1alpha1.HealthReport {
+	report := v1alpha1.NewHealthReport(update.Statuses, time.Now(), cluster)
+	if update.Duration > 0 {
+		report.EvaluationDuration = &metav1.Duration{Duration: update.Duration}
 	}
-
-	for _, ss := range s.SubStatuses {
-		ret.Subobjects = append(ret.Subobjects, wrapObjectStatus(ss))
+	report.Wait = newWaitInfo(info)
+	if update.Error != nil {
+		report.Error = update.Error.Error()
 	}
-
-	return &ret
-}
-
-func (p KubectlPrinter) PrintStatuses(statuses []status.ObjectStatus, w io.Writer) {
-	objects := make([]runtime.Object, 0, len(statuses))
-	for _, s := range statuses {
-		objects = append(objects, wrapObjectStatus(s))
-	}
-
-	list := &corev1.List{
-		TypeMeta: metav1.TypeMeta{
-			Kind:       "List",
-			APIVersion: "v1",
-		},
-		ListMeta: metav1.ListMeta{},
-	}
-	if err := meta.SetList(list, objects); err != nil {
-		panic(err)
-	}
-
-	p.Printer.PrintObj(list, w)
+	return report
 }
@@ -0,0 +1,49 @@
+package print
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	"github.com/inecas/kube-health/pkg/api/v1alpha1"
+	"github.com/inecas/kube-health/pkg/eval"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+func TestKubectlPrinter(t *testing.T) {
+	p := KubectlPrinter{Printer: &printers.JSONPrinter{}, Cluster: &v1alpha1.ClusterInfo{Context: "prod"}}
+
+	sb := &strings.Builder{}
+	p.PrintStatuses([]status.ObjectStatus{testDeployment()}, sb)
+	var report v1alpha1.HealthReport
+	require.NoError(t, json.Unmarshal([]byte(sb.String()), &report))
+	assert.Equal(t, "prod", report.Cluster.Context)
+	assert.Equal(t, 4, report.Summary.Objects)
+	assert.Empty(t, report.Error)
+
+	sb.Reset()
+	p.PrintUpdate(eval.StatusUpdate{Error: errors.New("connection refused")}, sb)
+	report = v1alpha1.HealthReport{}
+	require.NoError(t, json.Unmarshal([]byte(sb.String()), &report))
+	assert.Equal(t, "connection refused", report.Error)
+	assert.Empty(t, report.Objects)
+}
+
+func TestKubectlPrinterFailure(t *testing.T) {
+	printer, err := printers.NewGoTemplatePrinter([]byte(`{{index .objects 5}}`))
+	require.NoError(t, err)
+	errOut := &strings.Builder{}
+	p := NewKubectlPrinter(printer, nil)
+	p.ErrOut = errOut
+
+	p.PrintStatuses([]status.ObjectStatus{testDeployment()}, &strings.Builder{})
+	p.PrintStatuses([]status.ObjectStatus{testDeployment()}, &strings.Builder{})
+	require.Error(t, p.Err())
+	assert.Equal(t, 1, strings.Count(errOut.String(), "Error: can't print the report: "),
+		"the failure is reported only once")
+}
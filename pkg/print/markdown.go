@@ -0,0 +1,136 @@
+package print
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+// MarkdownPrinter prints the statuses as a Markdown report, e.g. for
+// incident tickets or pull request comments. It starts with a table of the
+// top-level objects, followed by a collapsible section per unhealthy object
+// with the tables of conditions of the object and its sub-objects.
+type MarkdownPrinter struct {
+	PrintOpts PrintOptions
+}
+
+func NewMarkdownPrinter(opts PrintOptions) *MarkdownPrinter {
+	return &MarkdownPrinter{PrintOpts: opts}
+}
+
+func (p *MarkdownPrinter) PrintStatuses(statuses []status.ObjectStatus, w io.Writer) {
+	var roots []status.ObjectStatus
+	for _, s := range statuses {
+		if s.Object != nil {
+			roots = append(roots, s)
+		}
+	}
+	sortObjectsBySeverity(roots)
+
+	fmt.Fprintf(w, "## kube-health report\n\n")
+	if len(roots) == 0 {
+		fmt.Fprintf(w, "No objects found.\n")
+		return
+	}
+
+	fmt.Fprintf(w, "| Result | Object |\n|---|---|\n")
+	for _, root := range roots {
+		fmt.Fprintf(w, "| %s | %s |\n", markdownStatus(root.Status()), markdownCell(p.objectName(root, nil)))
+	}
+
+	for _, root := range roots {
+		if !p.shouldPrintDetails(root) {
+			continue
+		}
+		fmt.Fprintf(w, "\n<details>\n<summary>%s %s</summary>\n", markdownStatus(root.Status()),
+			markdownCell(p.objectName(root, nil)))
+		walkTree(root, func(obj status.ObjectStatus, path []status.ObjectStatus) {
+			// Skip the healthy parts of the tree, the same way as the tree printer.
+			for _, parent := range path {
+				if !p.shouldPrintDetails(parent) {
+					return
+				}
+			}
+			p.printObject(w, obj, path)
+		})
+		fmt.Fprintf(w, "\n</details>\n")
+	}
+}
+
+func (p *MarkdownPrinter) shouldPrintDetails(obj status.ObjectStatus) bool {
+	return p.PrintOpts.ShowOk || obj.Status().Result > status.Ok || obj.Status().Progressing
+}
+
+func (p *MarkdownPrinter) objectName(obj status.ObjectStatus, path []status.ObjectStatus) string {
+	name := relativeName(obj.Object, path)
+	if p.PrintOpts.ShowGroup {
+		name += fmt.Sprintf(" [%s]", obj.Object.GroupVersionKind().Group)
+	}
+	return name
+}
+
+func (p *MarkdownPrinter) printObject(w io.Writer, obj status.ObjectStatus, path []status.ObjectStatus) {
+	fmt.Fprintf(w, "\n**%s** `%s`\n", markdownStatus(obj.Status()), p.objectName(obj, path))
+	if obj.ObjStatus.Err != nil {
+		fmt.Fprintf(w, "\nError: %s\n", markdownCell(obj.ObjStatus.Err.Error()))
+	}
+	if len(obj.Conditions) == 0 || !p.shouldPrintDetails(obj) {
+		return
+	}
+
+	fmt.Fprintf(w, "\n| Condition | Status | Result | Age | Reason | Message |\n|---|---|---|---|---|---|\n")
+	for _, cond := range obj.Conditions {
+		fmt.Fprintf(w, "| %s | %s | %s | %s | %s | %s |\n",
+			markdownCell(cond.Type),
+			markdownCell(string(cond.Condition.Status)),
+			markdownStatus(cond.Status()),
+			formatConditionAge(p.PrintOpts, cond),
+			markdownCell(cond.Reason),
+			markdownCell(strings.TrimSpace(cond.Message)))
+	}
+}
+
+func markdownStatus(s status.Status) string {
+	var icon string
+	switch s.Result {
+	case status.Ok:
+		icon = "🟢"
+	case status.Warning:
+		icon = "🟡"
+	case status.Error:
+		icon = "🔴"
+	default:
+		icon = "⚪"
+	}
+	ret := icon + " " + s.Result.String()
+	if s.Progressing {
+		ret += " (progressing)"
+	}
+	return ret
+}
+
+var markdownCellReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"~", `\~`,
+	"[", `\[`,
+	"]", `\]`,
+	"|", `\|`,
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	// Prevent mentioning the users and teams when posted to GitHub or GitLab.
+	"@", "&#64;",
+	"\r", "",
+	"\n", "<br>",
+)
+
+// markdownCell escapes the text to fit into a single table cell, rendered
+// verbatim: without any formatting, links, HTML tags or mentions.
+func markdownCell(s string) string {
+	return markdownCellReplacer.Replace(s)
+}
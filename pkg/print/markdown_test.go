@@ -0,0 +1,58 @@
+package print
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inecas/kube-health/internal/test"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+func TestMarkdownPrinter(t *testing.T) {
+	statuses := []status.ObjectStatus{testStatus("Service", "api", status.Ok), testDeployment()}
+
+	sb := &strings.Builder{}
+	NewMarkdownPrinter(PrintOptions{}).PrintStatuses(statuses, sb)
+	test.AssertStr(t, `
+## kube-health report
+
+| Result | Object |
+|---|---|
+| 🔴 Error | default/Deployment/web |
+| 🟢 Ok | default/Service/api |
+
+<details>
+<summary>🔴 Error default/Deployment/web</summary>
+
+**🔴 Error** `+"`default/Deployment/web`"+`
+
+**🔴 Error** `+"`ReplicaSet/web-1`"+`
+
+**🟢 Ok** `+"`ReplicaSet/web-1 > Pod/web-1-a`"+`
+
+**🔴 Error** `+"`ReplicaSet/web-1 > Pod/web-1-b`"+`
+
+| Condition | Status | Result | Age | Reason | Message |
+|---|---|---|---|---|---|
+| Ready | False | 🔴 Error |  | ContainersNotReady | containers with unready status: \[web\] &lt;script&gt;alert("x")&lt;/script&gt; &amp; more<br>see \| logs |
+| PodScheduled | False | 🟢 Ok |  |  |  |
+
+</details>
+	`, sb.String())
+
+	sb = &strings.Builder{}
+	NewMarkdownPrinter(PrintOptions{}).PrintStatuses(nil, sb)
+	test.AssertStr(t, `
+## kube-health report
+
+No objects found.
+	`, sb.String())
+}
+
+func TestMarkdownCell(t *testing.T) {
+	assert.Equal(t, `\*bold\* \_em\_ \~strike\~ \`+"`code\\`"+` \[link\](http://x) &#64;team a\|b &lt;br&gt; &amp;amp; \\`,
+		markdownCell("*bold* _em_ ~strike~ `code` [link](http://x) @team a|b <br> &amp; \\"))
+	assert.Equal(t, "first<br>second", markdownCell("first\r\nsecond"))
+}
@@ -0,0 +1,167 @@
+package print
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/inecas/kube-health/pkg/eval"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+// kindCount holds the number of objects of a kind per result.
+type kindCount struct {
+	gk          schema.GroupKind
+	total       int
+	ok          int
+	warning     int
+	error       int
+	unknown     int
+	progressing int
+}
+
+var overviewCols = []Column{
+	{
+		Header:   "KIND",
+		Width:    40,
+		FormatFn: FormatFn(formatKindCountKind),
+	},
+	countColumn("OBJECTS", nil, func(c kindCount) int { return c.total }),
+	countColumn("OK", &status.Status{Result: status.Ok}, func(c kindCount) int { return c.ok }),
+	countColumn("WARNING", &status.Status{Result: status.Warning}, func(c kindCount) int { return c.warning }),
+	countColumn("ERROR", &status.Status{Result: status.Error}, func(c kindCount) int { return c.error }),
+	countColumn("UNKNOWN", nil, func(c kindCount) int { return c.unknown }),
+	countColumn("PROGRESSING", &status.Status{Progressing: true}, func(c kindCount) int { return c.progressing }),
+}
+
+// countColumn shows the count colored by the status, if set.
+func countColumn(header string, s *status.Status, countFn func(kindCount) int) Column {
+	return Column{
+		Header: header,
+		Width:  len(header),
+		FormatFn: FormatFn(func(o PrintOptions, c kindCount) string {
+			count := countFn(c)
+			if o.Color && s != nil && count > 0 {
+				if color, setColor := statusColor(o, *s); setColor {
+					return SprintfWithColor(color, "%d", count)
+				}
+			}
+			return fmt.Sprintf("%d", count)
+		}),
+	}
+}
+
+func formatKindCountKind(o PrintOptions, c kindCount) string {
+	if o.ShowGroup {
+		return c.gk.String()
+	}
+	return c.gk.Kind
+}
+
+// OverviewPrinter prints a condensed report about many objects, e.g. all
+// top-level objects in a namespace: number of objects per kind and result,
+// followed by the trees of the unhealthy objects only.
+type OverviewPrinter struct {
+	*TreePrinter
+	// FirstKinds are listed before the other kinds, in the given order.
+	FirstKinds []schema.GroupKind
+}
+
+func NewOverviewPrinter(opts PrintOptions, firstKinds []schema.GroupKind) *OverviewPrinter {
+	return &OverviewPrinter{
+		TreePrinter: NewTreePrinter(opts),
+		FirstKinds:  firstKinds,
+	}
+}
+
+func (p *OverviewPrinter) PrintStatuses(objects []status.ObjectStatus, w io.Writer) {
+	p.printHeader(w, overviewCols)
+	for _, c := range p.countByKind(objects) {
+		p.printRow(w, formatRow(overviewCols, p.PrintOpts, c), "", "")
+	}
+
+	var details []status.ObjectStatus
+	for _, obj := range objects {
+		if p.shouldPrintDetails(obj) {
+			details = append(details, obj)
+		}
+	}
+	if len(details) == 0 {
+		return
+	}
+
+	p.printf(w, "\n")
+	p.printHeader(w, conditionsCols)
+	for _, group := range p.groupByFirstKinds(details) {
+		p.printObjects(w, group)
+	}
+}
+
+func (p *OverviewPrinter) PrintUpdate(update eval.StatusUpdate, w io.Writer) {
+	printWithSummary(p, p.PrintOpts, p.Info, update, w)
+}
+
+// countByKind returns the counts with the FirstKinds first, followed by
+// the rest of the kinds in alphabetical order.
+func (p *OverviewPrinter) countByKind(objects []status.ObjectStatus) []kindCount {
+	counts := make(map[schema.GroupKind]*kindCount)
+	for _, obj := range objects {
+		gk := obj.Object.GroupVersionKind().GroupKind()
+		c, found := counts[gk]
+		if !found {
+			c = &kindCount{gk: gk}
+			counts[gk] = c
+		}
+
+		s := obj.Status()
+		c.total++
+		switch s.Result {
+		case status.Ok:
+			c.ok++
+		case status.Warning:
+			c.warning++
+		case status.Error:
+			c.error++
+		default:
+			c.unknown++
+		}
+		if s.Progressing {
+			c.progressing++
+		}
+	}
+
+	ret := make([]kindCount, 0, len(counts))
+	for _, c := range counts {
+		ret = append(ret, *c)
+	}
+	slices.SortFunc(ret, func(a, b kindCount) int {
+		if diff := p.kindOrder(a.gk) - p.kindOrder(b.gk); diff != 0 {
+			return diff
+		}
+		return strings.Compare(a.gk.String(), b.gk.String())
+	})
+	return ret
+}
+
+// groupByFirstKinds splits the objects to groups to be printed one after
+// another: one group per each of FirstKinds and one for the rest.
+func (p *OverviewPrinter) groupByFirstKinds(objects []status.ObjectStatus) [][]status.ObjectStatus {
+	groups := make([][]status.ObjectStatus, len(p.FirstKinds)+1)
+	for _, obj := range objects {
+		i := p.kindOrder(obj.Object.GroupVersionKind().GroupKind())
+		groups[i] = append(groups[i], obj)
+	}
+	return groups
+}
+
+// kindOrder returns the position of the kind in FirstKinds, or len(FirstKinds)
+// for other kinds.
+func (p *OverviewPrinter) kindOrder(gk schema.GroupKind) int {
+	if i := slices.Index(p.FirstKinds, gk); i >= 0 {
+		return i
+	}
+	return len(p.FirstKinds)
+}
@@ -19,6 +19,8 @@ type PeriodicPrinter struct {
 	previousLines int
 	updateChan    <-chan eval.StatusUpdate
 	callback      func([]status.ObjectStatus)
+	// redraw replaces the previous update on the screen.
+	redraw bool
 }
 
 type lineCountWriter struct {
@@ -36,6 +38,20 @@ func (lcw *lineCountWriter) Write(p []byte) (n int, err error) {
 }
 
 func NewPeriodicPrinter(printer StatusPrinter, out OutStreams, updateChan <-chan eval.StatusUpdate,
+	callback func([]status.ObjectStatus)) *PeriodicPrinter {
+	return &PeriodicPrinter{
+		printer:    printer,
+		out:        out,
+		updateChan: updateChan,
+		callback:   callback,
+		redraw:     true,
+	}
+}
+
+// NewStreamingPrinter creates the printer appending every update to the
+// output, without any terminal control sequences. It's used for outputs
+// consumed by other programs, such as JSON Lines.
+func NewStreamingPrinter(printer StatusPrinter, out OutStreams, updateChan <-chan eval.StatusUpdate,
 	callback func([]status.ObjectStatus)) *PeriodicPrinter {
 	return &PeriodicPrinter{
 		printer:    printer,
@@ -48,14 +64,16 @@ func NewPeriodicPrinter(printer StatusPrinter, out OutStreams, updateChan <-chan
 func (p *PeriodicPrinter) Start() {
 	for update := range p.updateChan {
 		if update.Error != nil {
-			fmt.Fprintf(p.out.Err, "Error: %s", update.Error)
+			fmt.Fprintf(p.out.Err, "Error: %s\n", update.Error)
 			p.previousLines = 0
 		}
-		p.resetScreen()
+		if p.redraw {
+			p.resetScreen()
+		}
 
 		// Wrap writer to count number of emited lines.
 		lcw := &lineCountWriter{w: p.out.Std}
-		p.printer.PrintStatuses(update.Statuses, lcw)
+		PrintStatusUpdate(p.printer, update, lcw)
 		p.previousLines = lcw.lines
 
 		if p.callback != nil {
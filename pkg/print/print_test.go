@@ -0,0 +1,71 @@
+package print
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+// testObject creates an object of the kind in the default namespace.
+func testObject(kind, name string) *status.Object {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("v1")
+	u.SetKind(kind)
+	u.SetNamespace("default")
+	u.SetName(name)
+	u.SetUID(types.UID(kind + "-" + name))
+	obj, err := status.NewObjectFromUnstructured(u)
+	if err != nil {
+		panic(err)
+	}
+	return obj
+}
+
+// testStatus creates a status of the object with the result.
+func testStatus(kind, name string, result status.Result, subStatuses ...status.ObjectStatus) status.ObjectStatus {
+	return status.ObjectStatus{
+		Object:      testObject(kind, name),
+		ObjStatus:   status.Status{Result: result, Status: result.String()},
+		SubStatuses: subStatuses,
+	}
+}
+
+// testCondition creates a condition status with the result.
+func testCondition(condType, reason, message string, result status.Result) status.ConditionStatus {
+	return status.ConditionStatus{
+		Condition: &metav1.Condition{
+			Type:    condType,
+			Status:  metav1.ConditionFalse,
+			Reason:  reason,
+			Message: message,
+		},
+		CondStatus: &status.Status{Result: result, Status: result.String()},
+	}
+}
+
+// testDeployment creates a failing deployment with a healthy and an unhealthy
+// pod. The message of the failing condition needs escaping in most formats.
+func testDeployment() status.ObjectStatus {
+	failing := testStatus("Pod", "web-1-b", status.Error)
+	failing.Conditions = []status.ConditionStatus{
+		testCondition("Ready", "ContainersNotReady",
+			"containers with unready status: [web] <script>alert(\"x\")</script> & more\nsee | logs", status.Error),
+		testCondition("PodScheduled", "", "", status.Ok),
+	}
+	healthy := testStatus("Pod", "web-1-a", status.Ok)
+	healthy.Conditions = []status.ConditionStatus{testCondition("Ready", "", "", status.Ok)}
+
+	rs := testStatus("ReplicaSet", "web-1", status.Error, failing, healthy)
+	return testStatus("Deployment", "web", status.Error, rs)
+}
+
+// testContainer creates a status of a container: an object without UID
+// and the raw data.
+func testContainer(name string, result status.Result) status.ObjectStatus {
+	obj := &status.Object{}
+	obj.Kind = "Container"
+	obj.Name = name
+	return status.ObjectStatus{Object: obj, ObjStatus: status.Status{Result: result, Status: result.String()}}
+}
@@ -7,10 +7,18 @@ import (
 )
 
 type PrintOptions struct {
-	ShowGroup bool // By default, group names are not shown.
-	ShowOk    bool // By default, OK statuses are not shown.
-	Width     int  // Width of the output. If 0, wrapping is disabled.
-	Color     bool // Use colors to indicate the health.
+	ShowGroup bool    // By default, group names are not shown.
+	ShowOk    bool    // By default, OK statuses are not shown.
+	Width     int     // Width of the output. If 0, wrapping is disabled.
+	Color     bool    // Use colors to indicate the health.
+	Explain   bool    // Show how the results were determined.
+	Theme     Theme   // Colors of the results. If not set, DarkTheme is used.
+	Symbols   Symbols // Symbols shown next to the results. By default, no symbols are shown.
+
+	MinResult         status.Result // Hide the objects with lower result. Unknown hides nothing.
+	MaxDepth          int           // Maximum depth of the printed sub-objects. If 0, the depth is not limited.
+	CollapseIdentical bool          // Fold identical sibling objects into a single line.
+	FullMessages      bool          // Don't truncate the long condition messages.
 }
 
 type OutStreams struct {
@@ -0,0 +1,142 @@
+package print
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/inecas/kube-health/pkg/api/v1alpha1"
+	"github.com/inecas/kube-health/pkg/eval"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+// RunInfo describes the run of kube-health. It's shown in the header and
+// the footer of the tree output and embedded in the structured output,
+// so that it's clear where and how the statuses were obtained.
+type RunInfo struct {
+	Context string    // kubeconfig context of the cluster, empty with multiple clusters (taken from the statuses)
+	Wait    string    // active wait strategy, empty when not waiting
+	Started time.Time // when the run started
+}
+
+// UpdatePrinter is implemented by printers showing details of the
+// evaluation besides the statuses, such as its duration.
+type UpdatePrinter interface {
+	PrintUpdate(update eval.StatusUpdate, w io.Writer)
+}
+
+// PrintStatusUpdate prints the update with the printer. Printers not
+// implementing UpdatePrinter print just the statuses.
+func PrintStatusUpdate(p StatusPrinter, update eval.StatusUpdate, w io.Writer) {
+	if up, ok := p.(UpdatePrinter); ok {
+		up.PrintUpdate(update, w)
+		return
+	}
+	p.PrintStatuses(update.Statuses, w)
+}
+
+// printWithSummary prints the statuses with the wait header and the summary
+// footer. Without the run info, only the statuses are printed.
+func printWithSummary(p StatusPrinter, o PrintOptions, info *RunInfo, update eval.StatusUpdate, w io.Writer) {
+	if info == nil {
+		p.PrintStatuses(update.Statuses, w)
+		return
+	}
+
+	if info.Wait != "" {
+		fmt.Fprintf(w, "Waiting for %s | elapsed: %s\n\n", info.Wait, formatDuration(time.Since(info.Started)))
+	}
+	p.PrintStatuses(update.Statuses, w)
+	fmt.Fprintf(w, "\n%s\n", formatSummary(o, info, update))
+}
+
+// formatSummary returns the footer with the totals per result, e.g.
+// "Summary: 1 Error, 0 Warning, 2 Ok, 0 Unknown, 1 Progressing | 12 objects evaluated in 340ms | context: prod".
+// With multiple clusters, the evaluated contexts are listed instead.
+func formatSummary(o PrintOptions, info *RunInfo, update eval.StatusUpdate) string {
+	s := summarize(update.Statuses)
+
+	count := func(n int, res status.Result, progressing bool) string {
+		text := fmt.Sprintf("%d %s", n, res)
+		if progressing {
+			text = fmt.Sprintf("%d Progressing", n)
+		}
+		if o.Color && n > 0 {
+			if color, setColor := statusColor(o, status.Status{Result: res, Progressing: progressing}); setColor {
+				text = SprintfWithColor(color, "%s", text)
+			}
+		}
+		return text
+	}
+	parts := []string{
+		"Summary: " + strings.Join([]string{
+			count(s.Error, status.Error, false),
+			count(s.Warning, status.Warning, false),
+			count(s.Ok, status.Ok, false),
+			count(s.Unknown, status.Unknown, false),
+			count(s.Progressing, status.Unknown, true),
+		}, ", "),
+	}
+
+	evaluated := fmt.Sprintf("%d objects evaluated", s.Objects)
+	if update.Duration > 0 {
+		evaluated += " in " + formatDuration(update.Duration)
+	}
+	parts = append(parts, evaluated)
+
+	if info.Context != "" {
+		parts = append(parts, "context: "+info.Context)
+	} else if contexts := clusterNames(update.Statuses); len(contexts) > 0 {
+		parts = append(parts, "contexts: "+strings.Join(contexts, ", "))
+	}
+	return strings.Join(parts, " | ")
+}
+
+// clusterNames returns the sorted names of the synthetic cluster objects,
+// i.e. the contexts evaluated in the multi-cluster mode.
+func clusterNames(statuses []status.ObjectStatus) []string {
+	var names []string
+	for _, s := range statuses {
+		if s.Object != nil && s.Object.IsCluster() {
+			names = append(names, s.Object.GetName())
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// summarize counts the top-level objects. The objects grouped under
+// synthetic cluster objects are counted instead of the clusters.
+func summarize(statuses []status.ObjectStatus) v1alpha1.Summary {
+	var roots []status.ObjectStatus
+	for _, s := range statuses {
+		if s.Object != nil && s.Object.IsCluster() {
+			roots = append(roots, s.SubStatuses...)
+		} else {
+			roots = append(roots, s)
+		}
+	}
+	return v1alpha1.Summarize(roots)
+}
+
+// newWaitInfo returns the wait details for the structured output.
+func newWaitInfo(info *RunInfo) *v1alpha1.WaitInfo {
+	if info == nil || info.Wait == "" {
+		return nil
+	}
+	wi := &v1alpha1.WaitInfo{Strategy: info.Wait}
+	wi.Elapsed.Duration = time.Since(info.Started).Round(time.Second)
+	return wi
+}
+
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	if d < time.Minute {
+		return d.Round(100 * time.Millisecond).String()
+	}
+	return d.Round(time.Second).String()
+}
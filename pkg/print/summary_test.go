@@ -0,0 +1,48 @@
+package print
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inecas/kube-health/pkg/api/v1alpha1"
+	"github.com/inecas/kube-health/pkg/eval"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+func TestSummarize(t *testing.T) {
+	progressing := testStatus("Deployment", "api", status.Ok)
+	progressing.ObjStatus.Progressing = true
+	statuses := []status.ObjectStatus{testDeployment(), progressing, testStatus("Service", "db", status.Unknown)}
+	assert.Equal(t, v1alpha1.Summary{Objects: 6, Ok: 1, Error: 1, Unknown: 1, Progressing: 1}, summarize(statuses))
+
+	// The objects in the clusters are counted instead of the clusters.
+	clusters := []status.ObjectStatus{
+		{Object: status.NewClusterObject("east"), ObjStatus: status.Status{Result: status.Error},
+			SubStatuses: statuses[:2]},
+		{Object: status.NewClusterObject("west"), ObjStatus: status.Status{Result: status.Unknown},
+			SubStatuses: statuses[2:]},
+	}
+	assert.Equal(t, summarize(statuses), summarize(clusters))
+}
+
+func TestFormatSummary(t *testing.T) {
+	statuses := []status.ObjectStatus{testDeployment(), testStatus("Service", "db", status.Ok)}
+	update := eval.StatusUpdate{Statuses: statuses, Duration: 342 * time.Millisecond}
+
+	assert.Equal(t, "Summary: 1 Error, 0 Warning, 1 Ok, 0 Unknown, 0 Progressing | 5 objects evaluated in 342ms | context: prod",
+		formatSummary(PrintOptions{}, &RunInfo{Context: "prod"}, update))
+
+	// Without the duration and context.
+	assert.Equal(t, "Summary: 1 Error, 0 Warning, 1 Ok, 0 Unknown, 0 Progressing | 5 objects evaluated",
+		formatSummary(PrintOptions{}, &RunInfo{}, eval.StatusUpdate{Statuses: statuses}))
+
+	// The evaluated clusters are listed in the multi-cluster mode.
+	update.Statuses = []status.ObjectStatus{
+		{Object: status.NewClusterObject("west"), SubStatuses: statuses[1:]},
+		{Object: status.NewClusterObject("east"), SubStatuses: statuses[:1]},
+	}
+	assert.Equal(t, "Summary: 1 Error, 0 Warning, 1 Ok, 0 Unknown, 0 Progressing | 5 objects evaluated in 342ms | contexts: east, west",
+		formatSummary(PrintOptions{}, &RunInfo{}, update))
+}
@@ -0,0 +1,388 @@
+package print
+
+// Code for printing the statuses as a flat table, one row per object.
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"k8s.io/client-go/util/jsonpath"
+
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+// tableRow is a single object in the flat table.
+type tableRow struct {
+	obj       status.ObjectStatus
+	cluster   string
+	namespace string // namespace of the object, or of its parent for sub-objects like containers
+	parent    string
+}
+
+// tableField is a predefined column of the table, usable in
+// custom-columns and --sort-by.
+type tableField struct {
+	name    string
+	header  string
+	format  func(o PrintOptions, r tableRow) string
+	compare func(a, b tableRow) int
+}
+
+var tableFields = []tableField{
+	{
+		name:   "cluster",
+		header: "CLUSTER",
+		format: func(o PrintOptions, r tableRow) string { return r.cluster },
+	},
+	{
+		name:   "namespace",
+		header: "NAMESPACE",
+		format: func(o PrintOptions, r tableRow) string { return r.namespace },
+	},
+	{
+		name:   "kind",
+		header: "KIND",
+		format: func(o PrintOptions, r tableRow) string { return r.obj.Object.Kind },
+	},
+	{
+		name:   "group",
+		header: "GROUP",
+		format: func(o PrintOptions, r tableRow) string { return r.obj.Object.GroupVersionKind().Group },
+	},
+	{
+		name:   "name",
+		header: "NAME",
+		format: func(o PrintOptions, r tableRow) string { return r.obj.Object.GetName() },
+	},
+	{
+		name:   "parent",
+		header: "PARENT",
+		format: func(o PrintOptions, r tableRow) string { return r.parent },
+	},
+	{
+		name:   "result",
+		header: "RESULT",
+		format: formatTableResult,
+		// The most severe first.
+		compare: func(a, b tableRow) int {
+			return severity(b.obj.Status().Result) - severity(a.obj.Status().Result)
+		},
+	},
+	{
+		name:   "progressing",
+		header: "PROGRESSING",
+		format: func(o PrintOptions, r tableRow) string { return fmt.Sprint(r.obj.Status().Progressing) },
+		// The progressing first.
+		compare: func(a, b tableRow) int {
+			return cmpBool(b.obj.Status().Progressing, a.obj.Status().Progressing)
+		},
+	},
+	{
+		name:   "status",
+		header: "STATUS",
+		format: func(o PrintOptions, r tableRow) string { return r.obj.Status().Status },
+	},
+	{
+		name:   "rootcause",
+		header: "ROOT CAUSE",
+		format: func(o PrintOptions, r tableRow) string { return rootCause(r.obj) },
+	},
+	{
+		name:   "age",
+		header: "AGE",
+		format: func(o PrintOptions, r tableRow) string {
+			return formatTimeSince(r.obj.Object.GetCreationTimestamp().Time)
+		},
+		// The oldest first.
+		compare: func(a, b tableRow) int {
+			return a.obj.Object.GetCreationTimestamp().Time.Compare(b.obj.Object.GetCreationTimestamp().Time)
+		},
+	},
+}
+
+// WideTableFields are the fields shown by the wide-table output.
+var WideTableFields = []string{"namespace", "kind", "name", "result", "progressing", "rootcause", "age"}
+
+func findTableField(name string) (tableField, bool) {
+	for _, f := range tableFields {
+		if strings.EqualFold(f.name, name) {
+			return f, true
+		}
+	}
+	return tableField{}, false
+}
+
+// TableFieldNames returns the names of the predefined fields.
+func TableFieldNames() []string {
+	names := make([]string, 0, len(tableFields))
+	for _, f := range tableFields {
+		names = append(names, f.name)
+	}
+	return names
+}
+
+func formatTableResult(o PrintOptions, r tableRow) string {
+	s := r.obj.Status()
+	ret := s.Result.String()
+	if o.Color {
+		if color, setColor := statusColor(o, s); setColor {
+			ret = SprintfWithColor(color, "%s", ret)
+		}
+	}
+	return statusSymbol(o, s) + ret
+}
+
+// rootCause describes the deepest unhealthy object in the sub-tree and its
+// first unhealthy condition.
+func rootCause(obj status.ObjectStatus) string {
+	cause, self := findRootCause(obj)
+	if cause.Object == nil {
+		return ""
+	}
+
+	detail := cause.Status().Status
+	if cond, found := firstUnhealthyCondition(cause); found {
+		detail = cond.Reason
+		if detail == "" {
+			detail = fmt.Sprintf("%s=%s", cond.Type, cond.Condition.Status)
+		}
+	}
+
+	if self {
+		return detail
+	}
+	name := fmt.Sprintf("%s/%s", cause.Object.Kind, cause.Object.GetName())
+	if detail == "" {
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", name, detail)
+}
+
+func firstUnhealthyCondition(obj status.ObjectStatus) (status.ConditionStatus, bool) {
+	for _, cond := range obj.Conditions {
+		if unhealthy(cond.Status()) {
+			return cond, true
+		}
+	}
+	return status.ConditionStatus{}, false
+}
+
+func findRootCause(obj status.ObjectStatus) (status.ObjectStatus, bool) {
+	if !unhealthy(obj.Status()) {
+		return status.ObjectStatus{}, false
+	}
+	sortObjects(obj.SubStatuses)
+	for _, sub := range obj.SubStatuses {
+		if cause, _ := findRootCause(sub); cause.Object != nil {
+			return cause, false
+		}
+	}
+	return obj, true
+}
+
+func cmpBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// TablePrinter prints the statuses as a flat table with one row per object,
+// suitable for sorting and grepping. The same object appearing under
+// multiple parents is printed only once.
+type TablePrinter struct {
+	PrintOpts PrintOptions
+	Columns   []Column
+	sortBy    *tableField
+	// wide adds the cluster column when printing objects from multiple clusters.
+	wide bool
+}
+
+// NewWideTablePrinter creates the printer with the WideTableFields columns.
+// The rows are sorted by the sortBy field, if not empty.
+func NewWideTablePrinter(opts PrintOptions, sortBy string) (*TablePrinter, error) {
+	spec := make([]string, 0, len(WideTableFields))
+	for _, name := range WideTableFields {
+		f, _ := findTableField(name)
+		spec = append(spec, f.header+":"+f.name)
+	}
+	p, err := NewCustomColumnsPrinter(opts, strings.Join(spec, ","), sortBy)
+	if err != nil {
+		return nil, err
+	}
+	p.wide = true
+	return p, nil
+}
+
+// NewCustomColumnsPrinter creates the printer with columns defined by the
+// spec in the HEADER:FIELD[,HEADER:FIELD...] format, similar to kubectl.
+// FIELD is either one of the TableFieldNames or a JSONPath expression
+// evaluated against the object (e.g. .metadata.labels.app).
+func NewCustomColumnsPrinter(opts PrintOptions, spec string, sortBy string) (*TablePrinter, error) {
+	if spec == "" {
+		return nil, fmt.Errorf("custom-columns format specified but no custom columns given")
+	}
+
+	p := &TablePrinter{PrintOpts: opts}
+	for _, colSpec := range strings.Split(spec, ",") {
+		header, field, found := strings.Cut(colSpec, ":")
+		if !found || header == "" || field == "" {
+			return nil, fmt.Errorf("unexpected custom-columns spec: %s, expected <header>:<field>", colSpec)
+		}
+		col, err := tableColumn(header, field)
+		if err != nil {
+			return nil, err
+		}
+		p.Columns = append(p.Columns, col)
+	}
+
+	if sortBy != "" {
+		f, found := findTableField(sortBy)
+		if !found {
+			return nil, fmt.Errorf("invalid sort-by %q: expected one of %s",
+				sortBy, strings.Join(TableFieldNames(), ", "))
+		}
+		p.sortBy = &f
+	}
+	return p, nil
+}
+
+func tableColumn(header, field string) (Column, error) {
+	if f, found := findTableField(field); found {
+		return Column{Header: header, FormatFn: FormatFn(f.format)}, nil
+	}
+
+	if !strings.HasPrefix(field, ".") && !strings.HasPrefix(field, "{") {
+		return Column{}, fmt.Errorf("unknown custom-columns field %q: expected a JSONPath expression or one of %s",
+			field, strings.Join(TableFieldNames(), ", "))
+	}
+	expr := field
+	if !strings.HasPrefix(expr, "{") {
+		expr = "{" + expr + "}"
+	}
+	jp := jsonpath.New(header).AllowMissingKeys(true)
+	if err := jp.Parse(expr); err != nil {
+		return Column{}, fmt.Errorf("invalid custom-columns field %q: %w", field, err)
+	}
+	return Column{
+		Header: header,
+		FormatFn: FormatFn(func(o PrintOptions, r tableRow) string {
+			if r.obj.Object.Unstructured == nil {
+				return "<none>"
+			}
+			buf := &bytes.Buffer{}
+			if err := jp.Execute(buf, r.obj.Object.Unstructured.Object); err != nil || buf.Len() == 0 {
+				return "<none>"
+			}
+			return buf.String()
+		}),
+	}, nil
+}
+
+func (p *TablePrinter) PrintStatuses(statuses []status.ObjectStatus, w io.Writer) {
+	rows := tableRows(statuses)
+	if p.sortBy != nil {
+		compare := p.sortBy.compare
+		if compare == nil {
+			format := p.sortBy.format
+			compare = func(a, b tableRow) int {
+				return strings.Compare(format(PrintOptions{}, a), format(PrintOptions{}, b))
+			}
+		}
+		slices.SortStableFunc(rows, compare)
+	}
+
+	cols := p.Columns
+	if p.wide && slices.ContainsFunc(rows, func(r tableRow) bool { return r.cluster != "" }) {
+		f, _ := findTableField("cluster")
+		cols = append([]Column{{Header: f.header, FormatFn: FormatFn(f.format)}}, cols...)
+	}
+
+	header := make([]Cell, len(cols))
+	for i, col := range cols {
+		header[i] = Cell{Column: col, Content: col.Header}
+	}
+	table := [][]Cell{header}
+	for _, r := range rows {
+		table = append(table, formatRow(cols, p.PrintOpts, r))
+	}
+
+	// Size the columns to fit the content.
+	widths := make([]int, len(cols))
+	for _, row := range table {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len([]rune(controlRe.ReplaceAllString(cell.Content, ""))))
+		}
+	}
+
+	for _, row := range table {
+		line := &strings.Builder{}
+		for i, cell := range row {
+			if i == len(row)-1 {
+				line.WriteString(cell.Content)
+			} else {
+				line.WriteString(padStringKeepControl(cell.Content, widths[i]) + cellSep)
+			}
+		}
+		fmt.Fprintln(w, strings.TrimRight(line.String(), " "))
+	}
+}
+
+// tableRows flattens the status trees in the same order as the tree is
+// printed. Synthetic cluster objects are not included: the objects in them
+// carry the cluster name instead.
+func tableRows(statuses []status.ObjectStatus) []tableRow {
+	var rows []tableRow
+	seen := make(map[string]bool)
+
+	add := func(obj status.ObjectStatus, cluster string, path []status.ObjectStatus) {
+		// Objects without UID (e.g. containers) are identified by the parent.
+		key := cluster + "|" + string(obj.Object.UID)
+		if obj.Object.UID == "" && len(path) > 0 && path[len(path)-1].Object.UID != "" {
+			key = fmt.Sprintf("%s|%s|%s/%s", cluster, path[len(path)-1].Object.UID, obj.Object.Kind, obj.Object.GetName())
+		} else if obj.Object.UID == "" {
+			key = cluster + "|" + pathKey(append(path[:len(path):len(path)], obj))
+		}
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+
+		r := tableRow{obj: obj, cluster: cluster, namespace: obj.Object.GetNamespace()}
+		for i := len(path) - 1; i >= 0 && r.namespace == ""; i-- {
+			r.namespace = path[i].Object.GetNamespace()
+		}
+		if len(path) > 0 {
+			parent := path[len(path)-1].Object
+			r.parent = fmt.Sprintf("%s/%s", parent.Kind, parent.GetName())
+		}
+		rows = append(rows, r)
+	}
+
+	sortObjects(statuses)
+	for _, root := range statuses {
+		if root.Object == nil {
+			continue
+		}
+		if root.Object.IsCluster() {
+			sortObjects(root.SubStatuses)
+			for _, sub := range root.SubStatuses {
+				walkTree(sub, func(obj status.ObjectStatus, path []status.ObjectStatus) {
+					add(obj, root.Object.GetName(), path)
+				})
+			}
+			continue
+		}
+		walkTree(root, func(obj status.ObjectStatus, path []status.ObjectStatus) {
+			add(obj, "", path)
+		})
+	}
+	return rows
+}
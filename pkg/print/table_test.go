@@ -0,0 +1,141 @@
+package print
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inecas/kube-health/internal/test"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+func TestNewCustomColumnsPrinter(t *testing.T) {
+	tests := []struct {
+		spec    string
+		sortBy  string
+		headers []string
+		err     string
+	}{
+		{spec: "NAME:name,APP:.metadata.labels.app,UID:{.metadata.uid}", headers: []string{"NAME", "APP", "UID"}},
+		{spec: "Kind:KIND", sortBy: "Result", headers: []string{"Kind"}},
+		{spec: "", err: "no custom columns given"},
+		{spec: "NAME", err: "unexpected custom-columns spec: NAME, expected <header>:<field>"},
+		{spec: ":name", err: "unexpected custom-columns spec: :name"},
+		{spec: "NAME:", err: "unexpected custom-columns spec: NAME:"},
+		{spec: "X:unknown", err: `unknown custom-columns field "unknown": expected a JSONPath expression or one of cluster,`},
+		{spec: "X:{.metadata", err: `invalid custom-columns field "{.metadata"`},
+		{spec: "NAME:name", sortBy: "labels", err: `invalid sort-by "labels": expected one of cluster,`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.spec, func(t *testing.T) {
+			p, err := NewCustomColumnsPrinter(PrintOptions{}, tc.spec, tc.sortBy)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			var headers []string
+			for _, col := range p.Columns {
+				headers = append(headers, col.Header)
+			}
+			assert.Equal(t, tc.headers, headers)
+		})
+	}
+}
+
+func TestTablePrinterJSONPath(t *testing.T) {
+	pod := testStatus("Pod", "web-1", status.Ok, testContainer("web", status.Ok))
+	pod.Object.Unstructured.SetLabels(map[string]string{"app": "web"})
+	job := testStatus("Job", "migrate", status.Ok)
+
+	p, err := NewCustomColumnsPrinter(PrintOptions{}, "NAME:name,APP:.metadata.labels.app,KIND:{.kind}", "")
+	require.NoError(t, err)
+	sb := &strings.Builder{}
+	p.PrintStatuses([]status.ObjectStatus{pod, job}, sb)
+	// Missing values and objects without the raw data are shown as <none>.
+	test.AssertStr(t, `
+NAME     APP     KIND
+migrate  <none>  Job
+web-1    web     Pod
+web      <none>  <none>
+	`, sb.String())
+}
+
+func TestTablePrinterSortBy(t *testing.T) {
+	statuses := func() []status.ObjectStatus {
+		ok := testStatus("Pod", "a-ok", status.Ok)
+		warning := testStatus("Pod", "b-warning", status.Warning)
+		warning.ObjStatus.Progressing = true
+		unknown := testStatus("Pod", "c-unknown", status.Unknown)
+		errored := testStatus("Pod", "d-error", status.Error)
+		return []status.ObjectStatus{ok, warning, unknown, errored}
+	}
+
+	tests := []struct {
+		sortBy   string
+		expected string
+	}{
+		{sortBy: "", expected: "a-ok b-warning c-unknown d-error"},
+		{sortBy: "result", expected: "d-error b-warning c-unknown a-ok"},
+		{sortBy: "progressing", expected: "b-warning a-ok c-unknown d-error"},
+		{sortBy: "status", expected: "d-error a-ok c-unknown b-warning"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.sortBy, func(t *testing.T) {
+			p, err := NewCustomColumnsPrinter(PrintOptions{}, "NAME:name", tc.sortBy)
+			require.NoError(t, err)
+			sb := &strings.Builder{}
+			p.PrintStatuses(statuses(), sb)
+			lines := strings.Split(strings.TrimSpace(sb.String()), "\n")
+			assert.Equal(t, tc.expected, strings.Join(lines[1:], " "))
+		})
+	}
+}
+
+func TestTableRows(t *testing.T) {
+	pod := testStatus("Pod", "web-1", status.Error, testContainer("web", status.Error))
+	services := []status.ObjectStatus{
+		testStatus("Service", "web", status.Error, pod),
+		testStatus("Service", "web-canary", status.Error, pod),
+	}
+
+	var rows []string
+	for _, r := range tableRows(services) {
+		rows = append(rows, r.cluster+"|"+r.namespace+"|"+r.parent+"|"+r.obj.Object.Kind+"/"+r.obj.Object.GetName())
+	}
+	// The pod and its container are listed only under the first service.
+	assert.Equal(t, []string{
+		"|default||Service/web",
+		"|default|Service/web|Pod/web-1",
+		"|default|Pod/web-1|Container/web",
+		"|default||Service/web-canary",
+	}, rows)
+
+	// The same objects in different clusters are different rows.
+	clusters := []status.ObjectStatus{
+		{Object: status.NewClusterObject("west"), SubStatuses: services},
+		{Object: status.NewClusterObject("east"), SubStatuses: services},
+	}
+	rows = nil
+	for _, r := range tableRows(clusters) {
+		rows = append(rows, r.cluster+"|"+r.obj.Object.Kind+"/"+r.obj.Object.GetName())
+	}
+	assert.Equal(t, []string{
+		"east|Service/web",
+		"east|Pod/web-1",
+		"east|Container/web",
+		"east|Service/web-canary",
+		"west|Service/web",
+		"west|Pod/web-1",
+		"west|Container/web",
+		"west|Service/web-canary",
+	}, rows)
+
+	p, err := NewWideTablePrinter(PrintOptions{}, "")
+	require.NoError(t, err)
+	sb := &strings.Builder{}
+	p.PrintStatuses(clusters[:1], sb)
+	assert.True(t, strings.HasPrefix(sb.String(), "CLUSTER  NAMESPACE  KIND"), sb.String())
+}
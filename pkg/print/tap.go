@@ -0,0 +1,105 @@
+package print
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+// tapProblem is an unhealthy condition or object reported in the YAML
+// diagnostic block of a test point.
+type tapProblem struct {
+	Object    string `yaml:"object"`
+	Condition string `yaml:"condition,omitempty"`
+	Result    string `yaml:"result"`
+	Reason    string `yaml:"reason,omitempty"`
+	Message   string `yaml:"message,omitempty"`
+}
+
+type tapDiagnostic struct {
+	Result      string       `yaml:"result"`
+	Progressing bool         `yaml:"progressing,omitempty"`
+	Problems    []tapProblem `yaml:"problems,omitempty"`
+}
+
+// TAPPrinter prints the statuses in the Test Anything Protocol (version 13).
+// Every root object is a test point, failing when the object is unhealthy.
+// The unhealthy conditions of the object and its sub-objects are listed
+// in the YAML diagnostic block. Objects with unknown result are skipped,
+// with the diagnostic block when their evaluation failed.
+type TAPPrinter struct{}
+
+func NewTAPPrinter() *TAPPrinter {
+	return &TAPPrinter{}
+}
+
+func (p *TAPPrinter) PrintStatuses(statuses []status.ObjectStatus, w io.Writer) {
+	var roots []status.ObjectStatus
+	for _, s := range statuses {
+		if s.Object != nil {
+			roots = append(roots, s)
+		}
+	}
+	sortObjects(roots)
+
+	fmt.Fprintf(w, "TAP version 13\n1..%d\n", len(roots))
+	for i, root := range roots {
+		s := root.Status()
+		switch {
+		case unhealthy(s):
+			fmt.Fprintf(w, "not ok %d - %s\n", i+1, objectName(root.Object))
+		case s.Result == status.Unknown:
+			fmt.Fprintf(w, "ok %d - %s # SKIP Unknown\n", i+1, objectName(root.Object))
+		default:
+			fmt.Fprintf(w, "ok %d - %s\n", i+1, objectName(root.Object))
+		}
+
+		if !unhealthy(s) && !s.Progressing && root.ObjStatus.Err == nil {
+			continue
+		}
+		p.printDiagnostic(w, root)
+	}
+}
+
+func (p *TAPPrinter) printDiagnostic(w io.Writer, root status.ObjectStatus) {
+	s := root.Status()
+	diag := tapDiagnostic{Result: s.Result.String(), Progressing: s.Progressing}
+	walkTree(root, func(obj status.ObjectStatus, path []status.ObjectStatus) {
+		name := relativeName(obj.Object, path)
+		if obj.ObjStatus.Err != nil {
+			diag.Problems = append(diag.Problems, tapProblem{
+				Object:  name,
+				Result:  obj.ObjStatus.Result.String(),
+				Message: obj.ObjStatus.Err.Error(),
+			})
+		}
+		for _, cond := range obj.Conditions {
+			if !unhealthy(cond.Status()) {
+				continue
+			}
+			diag.Problems = append(diag.Problems, tapProblem{
+				Object:    name,
+				Condition: fmt.Sprintf("%s=%s", cond.Type, cond.Condition.Status),
+				Result:    cond.Status().Result.String(),
+				Reason:    cond.Reason,
+				Message:   strings.TrimSpace(cond.Message),
+			})
+		}
+	})
+
+	out := &strings.Builder{}
+	enc := yaml.NewEncoder(out)
+	enc.SetIndent(2)
+	if err := enc.Encode(diag); err != nil {
+		panic(err)
+	}
+	fmt.Fprintln(w, "  ---")
+	for _, line := range strings.Split(strings.TrimRight(out.String(), "\n"), "\n") {
+		fmt.Fprintf(w, "  %s\n", line)
+	}
+	fmt.Fprintln(w, "  ...")
+}
@@ -0,0 +1,53 @@
+package print
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/inecas/kube-health/internal/test"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+func TestTAPPrinter(t *testing.T) {
+	ok := testStatus("Service", "api", status.Ok)
+	unknown := testStatus("Service", "db", status.Unknown)
+	progressing := testStatus("StatefulSet", "cache", status.Ok)
+	progressing.ObjStatus.Progressing = true
+	failed := testStatus("ConfigMap", "config", status.Unknown)
+	failed.ObjStatus.Err = errors.New("forbidden: no access")
+
+	sb := &strings.Builder{}
+	NewTAPPrinter().PrintStatuses([]status.ObjectStatus{ok, unknown, testDeployment(), progressing, failed, {}}, sb)
+	test.AssertStr(t, `
+TAP version 13
+1..5
+ok 1 - default/ConfigMap/config # SKIP Unknown
+  ---
+  result: Unknown
+  problems:
+    - object: default/ConfigMap/config
+      result: Unknown
+      message: 'forbidden: no access'
+  ...
+not ok 2 - default/Deployment/web
+  ---
+  result: Error
+  problems:
+    - object: ReplicaSet/web-1 > Pod/web-1-b
+      condition: Ready=False
+      result: Error
+      reason: ContainersNotReady
+      message: |-
+        containers with unready status: [web] <script>alert("x")</script> & more
+        see | logs
+  ...
+ok 3 - default/Service/api
+ok 4 - default/Service/db # SKIP Unknown
+ok 5 - default/StatefulSet/cache
+  ---
+  result: Ok
+  progressing: true
+  ...
+	`, sb.String())
+}
@@ -0,0 +1,125 @@
+package print
+
+// Color themes and status symbols.
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+// Theme defines the colors used for the results. Unknown results are
+// not colored.
+type Theme struct {
+	Ok          Color
+	Warning     Color
+	Error       Color
+	Progressing Color
+}
+
+var (
+	// DarkTheme is the default theme, for terminals with dark background.
+	DarkTheme = Theme{Ok: GREEN, Warning: YELLOW, Error: RED, Progressing: YELLOW}
+	// LightTheme avoids yellow, which is hard to read on light background.
+	LightTheme = Theme{Ok: GREEN, Warning: MAGENTA, Error: RED, Progressing: BLUE}
+	// HighContrastTheme uses bright colors and avoids distinguishing
+	// the results by red and green only.
+	HighContrastTheme = Theme{Ok: BRIGHT_BLUE, Warning: BRIGHT_YELLOW, Error: BRIGHT_RED, Progressing: BRIGHT_MAGENTA}
+)
+
+var themes = map[string]Theme{
+	"dark":          DarkTheme,
+	"light":         LightTheme,
+	"high-contrast": HighContrastTheme,
+}
+
+// ThemeNames returns the names of the available themes.
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ParseTheme returns the theme by name.
+func ParseTheme(name string) (Theme, error) {
+	theme, found := themes[strings.ToLower(name)]
+	if !found {
+		return Theme{}, fmt.Errorf("unknown theme %q: expected one of %s", name, strings.Join(ThemeNames(), ", "))
+	}
+	return theme, nil
+}
+
+// Symbols are shown next to the results, so that the results can be told
+// apart without colors.
+type Symbols struct {
+	Ok          string
+	Warning     string
+	Error       string
+	Unknown     string
+	Progressing string
+}
+
+var (
+	UnicodeSymbols = Symbols{Ok: "✓", Warning: "⚠", Error: "✗", Unknown: "?", Progressing: "⟳"}
+	ASCIISymbols   = Symbols{Ok: "+", Warning: "!", Error: "x", Unknown: "?", Progressing: "~"}
+)
+
+// ParseSymbols returns the symbols by name: none, unicode or ascii.
+func ParseSymbols(name string) (Symbols, error) {
+	switch strings.ToLower(name) {
+	case "", "none":
+		return Symbols{}, nil
+	case "unicode":
+		return UnicodeSymbols, nil
+	case "ascii":
+		return ASCIISymbols, nil
+	}
+	return Symbols{}, fmt.Errorf("unknown symbols %q: expected one of none, unicode, ascii", name)
+}
+
+func statusColor(o PrintOptions, s status.Status) (Color, bool) {
+	theme := o.Theme
+	if theme == (Theme{}) {
+		theme = DarkTheme
+	}
+
+	var color Color
+	switch {
+	case s.Progressing:
+		color = theme.Progressing
+	case s.Result == status.Ok:
+		color = theme.Ok
+	case s.Result == status.Warning:
+		color = theme.Warning
+	case s.Result == status.Error:
+		color = theme.Error
+	}
+	return color, color != 0
+}
+
+// statusSymbol returns the symbol for the status, followed by a space,
+// or an empty string when the symbols are disabled.
+func statusSymbol(o PrintOptions, s status.Status) string {
+	var symbol string
+	switch {
+	case s.Progressing:
+		symbol = o.Symbols.Progressing
+	case s.Result == status.Ok:
+		symbol = o.Symbols.Ok
+	case s.Result == status.Warning:
+		symbol = o.Symbols.Warning
+	case s.Result == status.Error:
+		symbol = o.Symbols.Error
+	default:
+		symbol = o.Symbols.Unknown
+	}
+	if symbol == "" {
+		return ""
+	}
+	return symbol + " "
+}
@@ -0,0 +1,48 @@
+package print
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTheme(t *testing.T) {
+	theme, err := ParseTheme("light")
+	assert.NoError(t, err)
+	assert.Equal(t, LightTheme, theme)
+
+	theme, err = ParseTheme("High-Contrast")
+	assert.NoError(t, err)
+	assert.Equal(t, HighContrastTheme, theme)
+
+	_, err = ParseTheme("solarized")
+	assert.EqualError(t, err, `unknown theme "solarized": expected one of dark, high-contrast, light`)
+
+	_, err = ParseTheme("")
+	assert.Error(t, err)
+}
+
+func TestParseSymbols(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected Symbols
+		err      string
+	}{
+		{name: "", expected: Symbols{}},
+		{name: "none", expected: Symbols{}},
+		{name: "unicode", expected: UnicodeSymbols},
+		{name: "ASCII", expected: ASCIISymbols},
+		{name: "emoji", err: `unknown symbols "emoji": expected one of none, unicode, ascii`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			symbols, err := ParseSymbols(tc.name)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, symbols)
+		})
+	}
+}
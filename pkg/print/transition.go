@@ -0,0 +1,170 @@
+package print
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/inecas/kube-health/pkg/eval"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+// TransitionPrinter prints status updates as a log of state transitions,
+// suitable for outputs that are not terminals (e.g. CI logs). Every time
+// an object or condition changes its result or progressing state, a single
+// timestamped line is printed. The full status is printed at the end, once
+// the update channel is closed.
+type TransitionPrinter struct {
+	printer    StatusPrinter
+	out        OutStreams
+	log        io.Writer
+	updateChan <-chan eval.StatusUpdate
+	callback   func([]status.ObjectStatus)
+
+	// entries holds the last known state of the objects and conditions.
+	entries map[string]transitionEntry
+}
+
+// transitionEntry is the state of an object or condition at the time of
+// an update.
+type transitionEntry struct {
+	label     string
+	state     string
+	condition bool
+	healthy   bool
+}
+
+// NewTransitionPrinter creates the printer. The transitions are written
+// to the log writer, while the final status is written to the standard
+// output.
+func NewTransitionPrinter(printer StatusPrinter, out OutStreams, log io.Writer,
+	updateChan <-chan eval.StatusUpdate, callback func([]status.ObjectStatus)) *TransitionPrinter {
+	return &TransitionPrinter{
+		printer:    printer,
+		out:        out,
+		log:        log,
+		updateChan: updateChan,
+		callback:   callback,
+	}
+}
+
+func (p *TransitionPrinter) Start() {
+	var last *eval.StatusUpdate
+	for update := range p.updateChan {
+		now := time.Now()
+		if update.Error != nil {
+			fmt.Fprintf(p.log, "%s Error: %s\n", formatTimestamp(now), update.Error)
+			continue
+		}
+
+		p.printTransitions(now, update.Statuses)
+		last = &update
+
+		if p.callback != nil {
+			p.callback(update.Statuses)
+		}
+	}
+
+	if last != nil {
+		fmt.Fprintln(p.log)
+		PrintStatusUpdate(p.printer, *last, p.out.Std)
+	}
+}
+
+func (p *TransitionPrinter) printTransitions(now time.Time, statuses []status.ObjectStatus) {
+	initial := p.entries == nil
+	entries := make(map[string]transitionEntry)
+
+	var keys []string
+	var walk func(objs []status.ObjectStatus, parentKey, parentLabel string)
+	walk = func(objs []status.ObjectStatus, parentKey, parentLabel string) {
+		sortObjects(objs)
+		for _, os := range objs {
+			if os.Object == nil {
+				continue
+			}
+			key, label := transitionKey(os.Object, parentKey, parentLabel)
+			if _, seen := entries[key]; seen {
+				// The same object can appear under multiple parents.
+				continue
+			}
+			keys = append(keys, key)
+			entries[key] = transitionEntry{
+				label: label,
+				state: transitionState(os.Status()),
+			}
+
+			for _, cond := range os.Conditions {
+				condKey := key + " " + cond.Type
+				keys = append(keys, condKey)
+				entries[condKey] = transitionEntry{
+					label:     label + " " + cond.Type,
+					state:     fmt.Sprintf("%s (%s)", cond.Condition.Status, transitionState(cond.Status())),
+					condition: true,
+					// Same as in the tree: conditions with unknown result are not
+					// considered abnormal.
+					healthy: cond.Status().Result <= status.Ok && !cond.Status().Progressing,
+				}
+			}
+			walk(os.SubStatuses, key, label)
+		}
+	}
+	walk(statuses, "", "")
+
+	ts := formatTimestamp(now)
+	for _, key := range keys {
+		e := entries[key]
+		old, found := p.entries[key]
+		switch {
+		case initial:
+			// Conditions of healthy objects would only add noise to the initial state.
+			if !e.condition || !e.healthy {
+				fmt.Fprintf(p.log, "%s %s: %s\n", ts, e.label, e.state)
+			}
+		case !found:
+			fmt.Fprintf(p.log, "%s %s: added %s\n", ts, e.label, e.state)
+		case old.state != e.state:
+			fmt.Fprintf(p.log, "%s %s: %s -> %s\n", ts, e.label, old.state, e.state)
+		}
+	}
+
+	var removed []transitionEntry
+	for key, old := range p.entries {
+		if _, found := entries[key]; !found && !old.condition {
+			removed = append(removed, old)
+		}
+	}
+	slices.SortFunc(removed, func(a, b transitionEntry) int { return strings.Compare(a.label, b.label) })
+	for _, old := range removed {
+		fmt.Fprintf(p.log, "%s %s: removed (was %s)\n", ts, old.label, old.state)
+	}
+
+	p.entries = entries
+}
+
+// transitionKey identifies the object across updates. Objects with UID are
+// identified by it, the rest (e.g. containers) by the path from the root.
+// Objects without namespace are labeled with the parent to be recognizable.
+func transitionKey(obj *status.Object, parentKey, parentLabel string) (string, string) {
+	label := fmt.Sprintf("%s/%s/%s", obj.GetNamespace(), obj.Kind, obj.GetName())
+	if obj.GetNamespace() == "" && parentLabel != "" {
+		label = fmt.Sprintf("%s %s/%s", parentLabel, obj.Kind, obj.GetName())
+	}
+	if obj.UID != "" {
+		return string(obj.UID), label
+	}
+	return fmt.Sprintf("%s|%s/%s/%s", parentKey, obj.Kind, obj.GetNamespace(), obj.GetName()), label
+}
+
+func transitionState(s status.Status) string {
+	if s.Progressing {
+		return s.Result.String() + ", progressing"
+	}
+	return s.Result.String()
+}
+
+func formatTimestamp(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
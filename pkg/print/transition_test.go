@@ -0,0 +1,106 @@
+package print
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inecas/kube-health/internal/test"
+	"github.com/inecas/kube-health/pkg/eval"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+// resultsPrinter prints the top-level objects with their results.
+type resultsPrinter struct{}
+
+func (resultsPrinter) PrintStatuses(statuses []status.ObjectStatus, w io.Writer) {
+	for _, os := range statuses {
+		fmt.Fprintf(w, "%s/%s %s\n", os.Object.Kind, os.Object.GetName(), os.Status().Result)
+	}
+}
+
+// transitionPod creates a pod with a container and the Ready and
+// PodScheduled conditions.
+func transitionPod(name string, result status.Result) status.ObjectStatus {
+	pod := testStatus("Pod", name, result, testContainer("app", result))
+	pod.Conditions = []status.ConditionStatus{
+		testCondition("Ready", "", "", result),
+		testCondition("PodScheduled", "", "", status.Ok),
+	}
+	return pod
+}
+
+func TestTransitionPrinter(t *testing.T) {
+	progressing := testStatus("Deployment", "web", status.Unknown, transitionPod("web-a", status.Warning))
+	progressing.ObjStatus.Progressing = true
+
+	updates := []eval.StatusUpdate{
+		{Statuses: []status.ObjectStatus{progressing}},
+		{Error: errors.New("connection refused")},
+		{Statuses: []status.ObjectStatus{
+			testStatus("Deployment", "web", status.Error,
+				transitionPod("web-a", status.Error), transitionPod("web-b", status.Ok)),
+		}},
+		// Unchanged state doesn't produce any lines.
+		{Statuses: []status.ObjectStatus{
+			testStatus("Deployment", "web", status.Error,
+				transitionPod("web-a", status.Error), transitionPod("web-b", status.Ok)),
+		}},
+		{Statuses: []status.ObjectStatus{
+			testStatus("Deployment", "web", status.Ok, transitionPod("web-b", status.Ok)),
+		}},
+	}
+	updateChan := make(chan eval.StatusUpdate, len(updates))
+	for _, update := range updates {
+		updateChan <- update
+	}
+	close(updateChan)
+
+	std, log := &strings.Builder{}, &strings.Builder{}
+	callbacks := 0
+	NewTransitionPrinter(resultsPrinter{}, OutStreams{Std: std, Err: &strings.Builder{}}, log, updateChan,
+		func([]status.ObjectStatus) { callbacks++ }).Start()
+
+	// The healthy conditions are left out of the initial state only.
+	timestamp := regexp.MustCompile(`(?m)^\S+ `)
+	test.AssertStr(t, `
+default/Deployment/web: Unknown, progressing
+default/Pod/web-a: Warning
+default/Pod/web-a Ready: False (Warning)
+default/Pod/web-a Container/app: Warning
+Error: connection refused
+default/Deployment/web: Unknown, progressing -> Error
+default/Pod/web-a: Warning -> Error
+default/Pod/web-a Ready: False (Warning) -> False (Error)
+default/Pod/web-a Container/app: Warning -> Error
+default/Pod/web-b: added Ok
+default/Pod/web-b Ready: added False (Ok)
+default/Pod/web-b PodScheduled: added False (Ok)
+default/Pod/web-b Container/app: added Ok
+default/Deployment/web: Error -> Ok
+default/Pod/web-a: removed (was Error)
+default/Pod/web-a Container/app: removed (was Error)
+`, timestamp.ReplaceAllString(log.String(), ""))
+
+	// The error updates don't reach the callback.
+	assert.Equal(t, 4, callbacks)
+	// The last successful update is printed in full at the end.
+	assert.Equal(t, "Deployment/web Ok\n", std.String())
+}
+
+func TestTransitionPrinterNoUpdates(t *testing.T) {
+	updateChan := make(chan eval.StatusUpdate, 1)
+	updateChan <- eval.StatusUpdate{Error: errors.New("connection refused")}
+	close(updateChan)
+
+	std, log := &strings.Builder{}, &strings.Builder{}
+	NewTransitionPrinter(resultsPrinter{}, OutStreams{Std: std, Err: &strings.Builder{}}, log, updateChan, nil).Start()
+
+	assert.Regexp(t, `^\S+ Error: connection refused\n$`, log.String())
+	assert.Empty(t, std.String(), "nothing to print without a successful update")
+}
@@ -12,6 +12,7 @@ import (
 
 	"k8s.io/utils/integer"
 
+	"github.com/inecas/kube-health/pkg/eval"
 	"github.com/inecas/kube-health/pkg/status"
 )
 
@@ -27,6 +28,9 @@ type Column struct {
 	MaxLineWrap int // Maximum number of lines to wrap the content to.
 	WrapPrefix  string
 	FormatFn    func(o PrintOptions, obj interface{}) string
+	// HighlightFn adds colors to the content. It's applied after wrapping
+	// to avoid splitting the control characters.
+	HighlightFn func(o PrintOptions, s string) string
 }
 
 // Cell is a single cell in a table in a specific column.
@@ -103,24 +107,41 @@ var (
 			MaxLineWrap: 3,
 			WrapPrefix:  "    ",
 			FormatFn:    FormatFn(formatConditionMessage),
+			HighlightFn: highlightErrors,
+		},
+	}
+	explainCols = []Column{
+		objectIndentCol,
+		blankColumn("", 0),
+		{
+			Header:      "EXPLAIN",
+			Width:       40,
+			MaxLineWrap: 5,
+			WrapPrefix:  "    ",
+			FormatFn:    FormatFn(formatExplain),
 		},
 	}
 )
 
 func formatConditionType(o PrintOptions, cond status.ConditionStatus) string {
+	symbol := statusSymbol(o, cond.Status())
+	if cond.Status().Result == status.Unknown && !cond.Status().Progressing {
+		// Conditions with unknown result are not considered abnormal.
+		symbol = ""
+	}
 	if o.Color {
-		color, setColor := statusColor(cond.Status())
+		color, setColor := statusColor(o, cond.Status())
 		if setColor {
-			return SprintfWithColor(color, "%s", cond.Type)
+			return symbol + SprintfWithColor(color, "%s", cond.Type)
 		} else {
-			return cond.Type
+			return symbol + cond.Type
 		}
 	} else {
 		ret := fmt.Sprintf("%s=%s", cond.Type, cond.Condition.Status)
 		if cond.CondStatus.Result > status.Ok {
 			ret = fmt.Sprintf("(%s) %s", cond.CondStatus.Result.String(), ret)
 		}
-		return ret
+		return symbol + ret
 	}
 }
 
@@ -128,28 +149,12 @@ func formatStatus(o PrintOptions, obj status.ObjectStatus) string {
 	s := obj.Status()
 	ret := statusMessage(s)
 	if o.Color {
-		color, setColor := statusColor(s)
+		color, setColor := statusColor(o, s)
 		if setColor {
 			ret = SprintfWithColor(color, "%s", ret)
 		}
 	}
-	return ret
-}
-
-func statusColor(s status.Status) (Color, bool) {
-	if s.Progressing {
-		return YELLOW, true
-	}
-
-	switch s.Result {
-	case status.Ok:
-		return GREEN, true
-	case status.Warning:
-		return YELLOW, true
-	case status.Error:
-		return RED, true
-	}
-	return 0, false
+	return statusSymbol(o, s) + ret
 }
 
 func statusMessage(s status.Status) string {
@@ -183,10 +188,46 @@ func formatConditionReason(o PrintOptions, cond status.ConditionStatus) string {
 	return cond.Reason
 }
 
+// messageCols returns the columns for the condition message. Long messages
+// are truncated unless FullMessages is set.
+func messageCols(o PrintOptions) []Column {
+	if !o.FullMessages {
+		return conditionMessageCols
+	}
+	cols := slices.Clone(conditionMessageCols)
+	cols[len(cols)-1].MaxLineWrap = 0
+	return cols
+}
+
 func formatConditionMessage(o PrintOptions, cond status.ConditionStatus) string {
 	return cond.Message
 }
 
+// highlightErrors colors the error-looking words, e.g. in the container logs.
+func highlightErrors(o PrintOptions, s string) string {
+	if !o.Color {
+		return s
+	}
+	color, setColor := statusColor(o, status.Status{Result: status.Error})
+	if !setColor {
+		return s
+	}
+
+	ret := &strings.Builder{}
+	last := 0
+	for _, loc := range eval.ErrorLogKeywords(s) {
+		ret.WriteString(s[last:loc[0]])
+		ret.WriteString(SprintfWithColor(color, "%s", s[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	ret.WriteString(s[last:])
+	return ret.String()
+}
+
+func formatExplain(o PrintOptions, s status.Status) string {
+	return fmt.Sprintf("why: %s", s.Provenance)
+}
+
 func formatObject(o PrintOptions, obj status.ObjectStatus, root, printGroups bool) string {
 	status := formatStatus(o, obj)
 	fullName := ""
@@ -199,6 +240,13 @@ func formatObject(o PrintOptions, obj status.ObjectStatus, root, printGroups boo
 	}
 
 	text := fmt.Sprintf("%s %s", status, fullName)
+	if len(obj.Overrides) > 0 {
+		overrides := make([]string, 0, len(obj.Overrides))
+		for _, o := range obj.Overrides {
+			overrides = append(overrides, o.String())
+		}
+		text += fmt.Sprintf(" (override: %s)", strings.Join(overrides, ", "))
+	}
 	return text
 }
 
@@ -206,6 +254,8 @@ func formatObject(o PrintOptions, obj status.ObjectStatus, root, printGroups boo
 // of resources in a tabular format.
 type TreePrinter struct {
 	PrintOpts PrintOptions
+	// Info adds the wait header and the summary footer. Optional.
+	Info *RunInfo
 }
 
 func NewTreePrinter(opts PrintOptions) *TreePrinter {
@@ -216,20 +266,36 @@ func NewTreePrinter(opts PrintOptions) *TreePrinter {
 
 func (t *TreePrinter) PrintStatuses(objects []status.ObjectStatus, w io.Writer) {
 	t.printHeader(w, conditionsCols)
+	t.printObjects(w, objects)
+}
 
-	sortObjects(objects)
+func (t *TreePrinter) PrintUpdate(update eval.StatusUpdate, w io.Writer) {
+	printWithSummary(t, t.PrintOpts, t.Info, update, w)
+}
+
+// treeItem is an object printed in the tree. With CollapseIdentical,
+// a single item can represent multiple identical sibling objects.
+type treeItem struct {
+	obj   status.ObjectStatus
+	count int
+	name  string // name pattern of the collapsed objects
+}
+
+// printObjects prints the objects with their sub-trees.
+func (t *TreePrinter) printObjects(w io.Writer, objects []status.ObjectStatus) {
+	objects = t.filterObjects(objects)
+	sortObjectsBySeverity(objects)
 
 	for _, obj := range objects {
-		subObjects := obj.SubStatuses
+		subItems := t.subItems(obj, 0)
 		prefixTail := ""
-		printSubResources := len(subObjects) > 0 && t.shouldPrintDetails(obj)
-		if printSubResources {
+		if len(subItems) > 0 {
 			prefixTail = "│ "
 		}
-		t.printObjectWithConditions(w, obj, "", prefixTail)
+		t.printObjectWithConditions(w, treeItem{obj: obj, count: 1}, "", prefixTail)
 
-		if printSubResources {
-			t.printSubTree(w, subObjects, "")
+		if len(subItems) > 0 {
+			t.printSubTree(w, subItems, "", 1)
 		}
 	}
 }
@@ -242,8 +308,48 @@ func (t *TreePrinter) shouldPrintDetails(obj status.ObjectStatus) bool {
 	return obj.Status().Result > status.Ok || obj.Status().Progressing
 }
 
-func (t *TreePrinter) printObjectWithConditions(w io.Writer, obj status.ObjectStatus, prefixHead, prefixTail string) {
-	t.printObject(w, obj, prefixHead)
+// filterObjects returns the objects with at least the MinResult result.
+func (t *TreePrinter) filterObjects(objects []status.ObjectStatus) []status.ObjectStatus {
+	if t.PrintOpts.MinResult == status.Unknown {
+		return objects
+	}
+	ret := make([]status.ObjectStatus, 0, len(objects))
+	for _, obj := range objects {
+		if passesMinResult(obj.Status().Result, t.PrintOpts.MinResult) {
+			ret = append(ret, obj)
+		}
+	}
+	return ret
+}
+
+// subItems returns the sub-objects to be printed under the object at the
+// given depth (0 for the top-level objects).
+func (t *TreePrinter) subItems(obj status.ObjectStatus, depth int) []treeItem {
+	if !t.shouldPrintDetails(obj) || (t.PrintOpts.MaxDepth > 0 && depth >= t.PrintOpts.MaxDepth) {
+		return nil
+	}
+	objects := t.filterObjects(obj.SubStatuses)
+	sortObjects(objects)
+	if t.PrintOpts.CollapseIdentical {
+		return collapseIdentical(objects)
+	}
+	items := make([]treeItem, 0, len(objects))
+	for _, o := range objects {
+		items = append(items, treeItem{obj: o, count: 1})
+	}
+	return items
+}
+
+func (t *TreePrinter) printObjectWithConditions(w io.Writer, item treeItem, prefixHead, prefixTail string) {
+	obj := item.obj
+	if item.count > 1 {
+		t.printf(w, "%s%s\n", prefixHead, formatCollapsedObject(t.PrintOpts, item))
+	} else {
+		t.printObject(w, obj, prefixHead)
+	}
+	if t.PrintOpts.Explain && obj.ObjStatus.Provenance != nil {
+		t.printRow(w, formatRow(explainCols, t.PrintOpts, obj.ObjStatus), prefixTail, prefixTail)
+	}
 	if t.shouldPrintDetails(obj) {
 		t.printConditions(w, obj, prefixTail)
 	}
@@ -258,7 +364,11 @@ func (t *TreePrinter) printConditions(w io.Writer, obj status.ObjectStatus, pref
 		row := formatRow(conditionsCols, t.PrintOpts, cond)
 		t.printRow(w, row, prefix, prefix)
 		if cond.Status().Result > status.Ok || cond.Status().Progressing {
-			row = formatRow(conditionMessageCols, t.PrintOpts, cond)
+			row = formatRow(messageCols(t.PrintOpts), t.PrintOpts, cond)
+			t.printRow(w, row, prefix, prefix)
+		}
+		if t.PrintOpts.Explain && cond.CondStatus != nil && cond.CondStatus.Provenance != nil {
+			row = formatRow(explainCols, t.PrintOpts, *cond.CondStatus)
 			t.printRow(w, row, prefix, prefix)
 		}
 	}
@@ -292,6 +402,9 @@ func (t *TreePrinter) printRow(w io.Writer, row []Cell, prefixHead, prefixTail s
 		}
 
 		cellTxt[i] = strings.TrimSpace(txt)
+		if cell.Column.HighlightFn != nil {
+			cellTxt[i] = cell.Column.HighlightFn(t.PrintOpts, cellTxt[i])
+		}
 
 		curWidth += width + len(cellSep)
 	}
@@ -341,11 +454,12 @@ func (t *TreePrinter) printRow(w io.Writer, row []Cell, prefixHead, prefixTail s
 // printSubTree prints out any subresources that belong to the
 // object. This function takes care of printing the correct tree
 // structure and indentation.
-func (t *TreePrinter) printSubTree(w io.Writer, objects []status.ObjectStatus, prefix string) {
-	sortObjects(objects)
-	for j, obj := range objects {
+func (t *TreePrinter) printSubTree(w io.Writer, items []treeItem, prefix string, depth int) {
+	for j, item := range items {
+		subItems := t.subItems(item.obj, depth)
+
 		var newPrefixHead, newPrefixTail string
-		if j < len(objects)-1 {
+		if j < len(items)-1 {
 			newPrefixHead = `├─ `
 			newPrefixTail = `│  `
 		} else {
@@ -353,21 +467,21 @@ func (t *TreePrinter) printSubTree(w io.Writer, objects []status.ObjectStatus, p
 			newPrefixTail = "   "
 		}
 
-		if t.shouldPrintDetails(obj) && len(obj.SubStatuses) > 0 {
+		if len(subItems) > 0 {
 			// Add an extra level of indentation if there are subresources to print.
 			newPrefixTail += "│ "
 		}
 
-		t.printObjectWithConditions(w, obj, prefix+newPrefixHead, prefix+newPrefixTail)
+		t.printObjectWithConditions(w, item, prefix+newPrefixHead, prefix+newPrefixTail)
 
 		var newPrefix string
-		if j < len(objects)-1 {
+		if j < len(items)-1 {
 			newPrefix = `│  `
 		} else {
 			newPrefix = "   "
 		}
-		if t.shouldPrintDetails(obj) {
-			t.printSubTree(w, obj.SubStatuses, prefix+newPrefix)
+		if len(subItems) > 0 {
+			t.printSubTree(w, subItems, prefix+newPrefix, depth+1)
 		}
 	}
 }
@@ -387,3 +501,94 @@ func sortObjects(objects []status.ObjectStatus) {
 		return strings.Compare(fullName(a), fullName(b))
 	})
 }
+
+// severity orders the results by how much attention they need: errors first,
+// then warnings, unknown and ok.
+func severity(r status.Result) int {
+	switch r {
+	case status.Error:
+		return 3
+	case status.Warning:
+		return 2
+	case status.Unknown:
+		return 1
+	}
+	return 0
+}
+
+// passesMinResult decides whether the result passes the minimal result
+// filter. Unknown minimum disables the filter. Objects that couldn't be
+// evaluated are always kept, as they might be hiding a problem.
+func passesMinResult(r, min status.Result) bool {
+	return min == status.Unknown || r == status.Unknown || severity(r) >= severity(min)
+}
+
+// sortObjectsBySeverity sorts the objects by the severity of the result,
+// progressing first, and by name within the same state.
+func sortObjectsBySeverity(objects []status.ObjectStatus) {
+	sortObjects(objects)
+	slices.SortStableFunc(objects, func(a, b status.ObjectStatus) int {
+		if c := severity(b.Status().Result) - severity(a.Status().Result); c != 0 {
+			return c
+		}
+		return cmpBool(b.Status().Progressing, a.Status().Progressing)
+	})
+}
+
+// collapseIdentical folds the sibling objects of the same kind with the same
+// status, conditions and root cause into a single item. The objects are
+// expected to be sorted.
+func collapseIdentical(objects []status.ObjectStatus) []treeItem {
+	var items []treeItem
+	index := make(map[string]int)
+	for _, obj := range objects {
+		sig := collapseSignature(obj)
+		if i, found := index[sig]; found {
+			items[i].count++
+			items[i].name = commonNamePattern(items[i].name, obj.Object.GetName())
+			continue
+		}
+		index[sig] = len(items)
+		items = append(items, treeItem{obj: obj, count: 1, name: obj.Object.GetName()})
+	}
+	return items
+}
+
+func collapseSignature(obj status.ObjectStatus) string {
+	s := obj.Status()
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "%s|%s|%v|%s|%s", obj.Object.GroupVersionKind().GroupKind(),
+		s.Result, s.Progressing, s.Status, rootCause(obj))
+	for _, cond := range obj.Conditions {
+		fmt.Fprintf(sb, "|%s=%s:%s:%s", cond.Type, cond.Condition.Status, cond.Reason, cond.Status().Result)
+	}
+	for _, o := range obj.Overrides {
+		fmt.Fprintf(sb, "|%s", o)
+	}
+	return sb.String()
+}
+
+// commonNamePattern returns the common prefix of the names followed by "*",
+// e.g. "foo-*" for "foo-abc" and "foo-def".
+func commonNamePattern(pattern, name string) string {
+	prefix := strings.TrimSuffix(pattern, "*")
+	i := 0
+	for i < len(prefix) && i < len(name) && prefix[i] == name[i] {
+		i++
+	}
+	if i == len(prefix) && i == len(name) && !strings.HasSuffix(pattern, "*") {
+		return pattern
+	}
+	return prefix[:i] + "*"
+}
+
+func formatCollapsedObject(o PrintOptions, item treeItem) string {
+	text := fmt.Sprintf("%s %s/%s (x%d)", formatStatus(o, item.obj), item.obj.Object.Kind, item.name, item.count)
+	if o.ShowGroup {
+		text += fmt.Sprintf(" [%s]", item.obj.Object.GroupVersionKind().Group)
+	}
+	if cause := rootCause(item.obj); cause != "" {
+		text += " " + cause
+	}
+	return text
+}
@@ -0,0 +1,36 @@
+package print
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/inecas/kube-health/internal/test"
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+func TestTreePrinterMinResult(t *testing.T) {
+	statuses := []status.ObjectStatus{
+		testStatus("Pod", "ok", status.Ok),
+		testStatus("Pod", "warning", status.Warning),
+		testStatus("Pod", "unknown", status.Unknown),
+		testStatus("Pod", "error", status.Error),
+	}
+
+	sb := &strings.Builder{}
+	NewTreePrinter(PrintOptions{MinResult: status.Warning}).PrintStatuses(statuses, sb)
+	test.AssertStr(t, `
+OBJECT           CONDITION                       AGE    REASON
+Error default/Pod/error
+Warning default/Pod/warning
+Unknown default/Pod/unknown
+	`, sb.String())
+
+	sb = &strings.Builder{}
+	NewTreePrinter(PrintOptions{MinResult: status.Error}).PrintStatuses(statuses, sb)
+	// The objects that couldn't be evaluated are kept.
+	test.AssertStr(t, `
+OBJECT           CONDITION                       AGE    REASON
+Error default/Pod/error
+Unknown default/Pod/unknown
+	`, sb.String())
+}
@@ -0,0 +1,49 @@
+package print
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+// walkTree visits the object and its sub-objects depth-first, in the same
+// order as the TreePrinter prints them. The path contains the ancestors of
+// the visited object, starting with the root.
+func walkTree(obj status.ObjectStatus, fn func(obj status.ObjectStatus, path []status.ObjectStatus)) {
+	var walk func(obj status.ObjectStatus, path []status.ObjectStatus)
+	walk = func(obj status.ObjectStatus, path []status.ObjectStatus) {
+		if obj.Object == nil {
+			return
+		}
+		fn(obj, path)
+		sortObjects(obj.SubStatuses)
+		for _, sub := range obj.SubStatuses {
+			walk(sub, append(path[:len(path):len(path)], obj))
+		}
+	}
+	walk(obj, nil)
+}
+
+// objectName returns the full name of a root object, as shown in the tree.
+func objectName(obj *status.Object) string {
+	return fmt.Sprintf("%s/%s/%s", obj.GetNamespace(), obj.Kind, obj.GetName())
+}
+
+// relativeName returns the name of the object relative to the root of the path.
+func relativeName(obj *status.Object, path []status.ObjectStatus) string {
+	if len(path) == 0 {
+		return objectName(obj)
+	}
+	parts := make([]string, 0, len(path))
+	for _, p := range path[1:] {
+		parts = append(parts, fmt.Sprintf("%s/%s", p.Object.Kind, p.Object.GetName()))
+	}
+	parts = append(parts, fmt.Sprintf("%s/%s", obj.Kind, obj.GetName()))
+	return strings.Join(parts, " > ")
+}
+
+// unhealthy returns true if the status should be reported as a problem.
+func unhealthy(s status.Status) bool {
+	return s.Result > status.Ok
+}
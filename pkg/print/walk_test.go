@@ -0,0 +1,37 @@
+package print
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inecas/kube-health/pkg/status"
+)
+
+func TestWalkTree(t *testing.T) {
+	var visited []string
+	walkTree(testDeployment(), func(obj status.ObjectStatus, path []status.ObjectStatus) {
+		visited = append(visited, relativeName(obj.Object, path))
+	})
+	// The sub-objects are visited depth-first, sorted by name.
+	assert.Equal(t, []string{
+		"default/Deployment/web",
+		"ReplicaSet/web-1",
+		"ReplicaSet/web-1 > Pod/web-1-a",
+		"ReplicaSet/web-1 > Pod/web-1-b",
+	}, visited)
+
+	// Placeholder statuses without an object are skipped.
+	visited = nil
+	walkTree(status.ObjectStatus{}, func(obj status.ObjectStatus, path []status.ObjectStatus) {
+		visited = append(visited, obj.Object.GetName())
+	})
+	assert.Empty(t, visited)
+}
+
+func TestUnhealthy(t *testing.T) {
+	assert.False(t, unhealthy(status.Status{Result: status.Unknown}))
+	assert.False(t, unhealthy(status.Status{Result: status.Ok}))
+	assert.True(t, unhealthy(status.Status{Result: status.Warning}))
+	assert.True(t, unhealthy(status.Status{Result: status.Error}))
+}
@@ -0,0 +1,33 @@
+package status
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// ClusterKind is the kind of the synthetic objects grouping the statuses
+	// per cluster, when evaluating multiple clusters at once.
+	ClusterKind = "Cluster"
+	// SyntheticAPIVersion is used for objects that don't exist in the cluster.
+	SyntheticAPIVersion = "kube-health.io/v1"
+)
+
+// NewClusterObject returns a synthetic object representing the cluster,
+// identified by the kubeconfig context name.
+func NewClusterObject(name string) *Object {
+	obj := &Object{
+		TypeMeta:     metav1.TypeMeta{APIVersion: SyntheticAPIVersion, Kind: ClusterKind},
+		ObjectMeta:   metav1.ObjectMeta{Name: name},
+		Unstructured: &unstructured.Unstructured{},
+	}
+	obj.Unstructured.SetAPIVersion(SyntheticAPIVersion)
+	obj.Unstructured.SetKind(ClusterKind)
+	obj.Unstructured.SetName(name)
+	return obj
+}
+
+// IsCluster returns true for the objects created by NewClusterObject.
+func (o *Object) IsCluster() bool {
+	return o.APIVersion == SyntheticAPIVersion && o.Kind == ClusterKind
+}
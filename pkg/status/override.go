@@ -0,0 +1,46 @@
+package status
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Annotations allowing to control the evaluation from the objects themselves.
+const (
+	// AnnotationIgnore set to "true" skips the evaluation of the object:
+	// it's reported as Ok.
+	AnnotationIgnore = "kube-health.io/ignore"
+
+	// AnnotationExpectedResult acknowledges a known state of the object
+	// (e.g. "warning"): results up to the expected one are reported as Ok.
+	AnnotationExpectedResult = "kube-health.io/expected-result"
+
+	// AnnotationIgnoreConditions is a comma-separated list of condition types
+	// that don't contribute to the object result.
+	AnnotationIgnoreConditions = "kube-health.io/ignore-conditions"
+
+	// AnnotationAggregation sets the policy for aggregating the sub-objects
+	// results (e.g. "any-ready" or "threshold=20").
+	AnnotationAggregation = "kube-health.io/aggregation"
+)
+
+// Override records an annotation that changed the evaluation result.
+type Override struct {
+	Annotation string `json:"annotation"`
+	Value      string `json:"value"`
+}
+
+func (o Override) String() string {
+	return fmt.Sprintf("%s=%s", strings.TrimPrefix(o.Annotation, "kube-health.io/"), o.Value)
+}
+
+// IgnoredStatus is the status of an object with evaluation turned off
+// via the AnnotationIgnore.
+func IgnoredStatus(obj *Object) ObjectStatus {
+	return ObjectStatus{
+		Object:     obj,
+		ObjStatus:  Status{Result: Ok, Status: "Ignored"},
+		Conditions: []ConditionStatus{},
+		Overrides:  []Override{{Annotation: AnnotationIgnore, Value: obj.GetAnnotations()[AnnotationIgnore]}},
+	}
+}
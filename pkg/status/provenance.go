@@ -0,0 +1,40 @@
+package status
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Provenance records how a status was determined. It's used to explain
+// the verdicts to the users (see the --explain flag).
+type Provenance struct {
+	// Analyzer that produced the status.
+	Analyzer string
+	// Rule that matched, e.g. the condition matcher or the aggregation policy.
+	Rule string
+	// ReversedPolarity is set for conditions where True means unhealthy.
+	ReversedPolarity bool
+	// Reason describes why the result was chosen, e.g. which condition
+	// or sub-object escalated the result to the object.
+	Reason string
+}
+
+func (p *Provenance) String() string {
+	if p == nil {
+		return ""
+	}
+	var parts []string
+	if p.Analyzer != "" {
+		parts = append(parts, fmt.Sprintf("analyzer: %s", p.Analyzer))
+	}
+	if p.Rule != "" {
+		parts = append(parts, fmt.Sprintf("rule: %s", p.Rule))
+	}
+	if p.ReversedPolarity {
+		parts = append(parts, "reversed polarity (True is unhealthy)")
+	}
+	if p.Reason != "" {
+		parts = append(parts, fmt.Sprintf("reason: %s", p.Reason))
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,17 @@
+package status
+
+// Relation describes how a sub-object was found from its parent object.
+type Relation string
+
+const (
+	// RelationNone is used for objects not found via a relation, e.g. the
+	// top-level objects or synthetic sub-objects like containers.
+	RelationNone Relation = ""
+	// RelationOwner is used for objects with the parent in owner references.
+	RelationOwner Relation = "owner"
+	// RelationSelector is used for objects matching the label selector
+	// of the parent.
+	RelationSelector Relation = "selector"
+	// RelationReference is used for objects referenced by the parent.
+	RelationReference Relation = "reference"
+)
@@ -2,6 +2,7 @@ package status
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -34,17 +35,52 @@ func (r Result) MarshalJSON() ([]byte, error) {
 	return json.Marshal(strings.ToLower(r.String()))
 }
 
+func (r *Result) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	res, err := ParseResult(s)
+	if err != nil {
+		return err
+	}
+	*r = res
+	return nil
+}
+
+// ParseResult converts the string representation of the result (as produced
+// by String or MarshalJSON) back to the Result value. The matching is
+// case-insensitive.
+func ParseResult(s string) (Result, error) {
+	for _, r := range []Result{Unknown, Ok, Warning, Error} {
+		if strings.EqualFold(s, r.String()) {
+			return r, nil
+		}
+	}
+	return Unknown, fmt.Errorf("unknown result %q", s)
+}
+
 // Status is the core structure representing the status of an object.
 type Status struct {
-	Result      Result `json:"result"`        // mapping to Result enum
-	Progressing bool   `json:"progressing"`   // true if the object is still progressing
-	Status      string `json:"-"`             // human readable status
-	Err         error  `json:"err,omitempty"` // error appeared during the evaluation
+	Result      Result      `json:"result"`          // mapping to Result enum
+	Progressing bool        `json:"progressing"`     // true if the object is still progressing
+	Status      string      `json:"-"`               // human readable status
+	Err         error       `json:"err,omitempty"`   // error appeared during the evaluation
+	Score       *int        `json:"score,omitempty"` // optional health score (0-100)
+	Provenance  *Provenance `json:"-"`               // how the status was determined (for explain mode)
 }
 
 func (in *Status) DeepCopy() *Status {
 	out := new(Status)
 	*out = *in
+	if in.Score != nil {
+		score := *in.Score
+		out.Score = &score
+	}
+	if in.Provenance != nil {
+		provenance := *in.Provenance
+		out.Provenance = &provenance
+	}
 	return out
 }
 
@@ -54,6 +90,8 @@ type ObjectStatus struct {
 	ObjStatus   Status            // overall status of the object
 	SubStatuses []ObjectStatus    // statuses of the sub-objects (e.g. pods of a replicaset)
 	Conditions  []ConditionStatus // conditions of the object
+	Overrides   []Override        // annotations that changed the evaluation result
+	Relation    Relation          // how the object was found from its parent
 }
 
 func (os ObjectStatus) Status() Status {